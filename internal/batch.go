@@ -0,0 +1,55 @@
+package internal
+
+import "sync"
+
+// BatchProgress is told each time a puzzle of a batch finishes, with the
+// number of puzzles done so far, the size of the batch and how many of the
+// finished puzzles could not be solved. Calls are never made concurrently.
+type BatchProgress func(done, total, failures int)
+
+// SolveBatch solves the puzzles in place using the given number of worker
+// goroutines, and returns the error from each puzzle's Solve by index. The
+// progress callback may be nil. Each puzzle still writes its steps to its own
+// output, so set that to io.Discard to keep workers from interleaving them.
+func SolveBatch(puzzles []*Sudoku, workers int, progress BatchProgress) []error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, len(puzzles))
+	indexes := make(chan int)
+
+	var mu sync.Mutex
+	done, failures := 0, 0
+	finished := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if err != nil {
+			failures++
+		}
+		if progress != nil {
+			progress(done, len(puzzles), failures)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = puzzles[i].Solve()
+				finished(errs[i])
+			}
+		}()
+	}
+
+	for i := range puzzles {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
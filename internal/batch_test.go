@@ -0,0 +1,34 @@
+package internal
+
+import "testing"
+
+func TestSolveBatchProgress(t *testing.T) {
+	puzzles := []*Sudoku{
+		loadPuzzle(t, "medium.txt"),
+		loadPuzzle(t, "hard1.txt"),
+		loadPuzzle(t, "blank.txt"),
+		loadPuzzle(t, "hard2.txt"),
+		loadPuzzle(t, "expert1.txt"),
+	}
+
+	calls, lastDone, lastFailures := 0, 0, 0
+	errs := SolveBatch(puzzles, 3, func(done, total, failures int) {
+		calls++
+		if total != len(puzzles) || done != lastDone+1 || failures < lastFailures {
+			t.Errorf("Progress went from %d done, %d failed to %d of %d done, %d failed", lastDone, lastFailures, done, total, failures)
+		}
+		lastDone, lastFailures = done, failures
+	})
+
+	if calls != len(puzzles) {
+		t.Errorf("Progress called %d times, want %d", calls, len(puzzles))
+	}
+	if lastFailures != 1 {
+		t.Errorf("Reported %d failures, want 1", lastFailures)
+	}
+	for i, err := range errs {
+		if (err != nil) != (i == 2) {
+			t.Errorf("Puzzle %d: %v", i+1, err)
+		}
+	}
+}
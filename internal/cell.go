@@ -1,17 +1,21 @@
 package internal
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 type Cell struct {
 	row   int
 	col   int
 	value int
 	moves Moves
+	given bool
 }
 
 func (c *Cell) Set(value int) error {
-	if value < 1 || value > 9 {
-		return fmt.Errorf("Cell value out of range: %s", value)
+	if value < 1 || value > Size {
+		return fmt.Errorf("Cell value out of range: %d", value)
 	}
 
 	if c.value != 0 {
@@ -24,13 +28,17 @@ func (c *Cell) Set(value int) error {
 	return nil
 }
 
+func (c *Cell) Given() bool {
+	return c.given
+}
+
 func (c *Cell) CanPlay(value int) bool {
 	return c.moves.Contains(value)
 }
 
 func (c *Cell) EliminateMove(value int) bool {
-	if value < 1 || value > 9 {
-		panic(fmt.Errorf("Value out of range: %s", value))
+	if value < 1 || value > Size {
+		panic(fmt.Errorf("Value out of range: %d", value))
 	}
 
 	return c.moves.Remove(value)
@@ -42,16 +50,42 @@ func (c *Cell) Moves() []int {
 
 type Cells []*Cell
 
-func (c Cells) FindMove(value int) Cells {
+func (c Cells) Filter(pred func(*Cell) bool) Cells {
 	cells := make(Cells, 0)
 	for _, cell := range c {
-		if cell.CanPlay(value) {
+		if pred(cell) {
 			cells = append(cells, cell)
 		}
 	}
 	return cells
 }
 
+func (c Cells) FindMove(value int) Cells {
+	return c.Filter(func(cell *Cell) bool {
+		return cell.CanPlay(value)
+	})
+}
+
+// PositionMask returns a bit for each cell that can hold value, with bit i
+// set for c[i].
+func (c Cells) PositionMask(value int) uint {
+	var positions uint
+	for i, cell := range c {
+		if cell.CanPlay(value) {
+			positions |= 1 << i
+		}
+	}
+	return positions
+}
+
+func (c Cells) ConjugatePair(value int) (Cells, bool) {
+	cells := c.FindMove(value)
+	if len(cells) != 2 {
+		return nil, false
+	}
+	return cells, true
+}
+
 func (c Cells) UnsetOnly() Cells {
 	cells := make(Cells, 0)
 	for _, cell := range c {
@@ -62,24 +96,64 @@ func (c Cells) UnsetOnly() Cells {
 	return cells
 }
 
+func (c Cells) Contains(cell *Cell) bool {
+	for _, candidate := range c {
+		if candidate.row == cell.row && candidate.col == cell.col {
+			return true
+		}
+	}
+	return false
+}
+
 func (c Cells) Excluding(other Cells) Cells {
 	difference := make(Cells, 0)
-
 	for _, candidate := range c {
-		excluded := false
-		for _, other := range other {
-			if candidate.row == other.row && candidate.col == other.col {
-				excluded = true
-				break
-			}
+		if !other.Contains(candidate) {
+			difference = append(difference, candidate)
 		}
+	}
+	return difference
+}
 
-		if !excluded {
-			difference = append(difference, candidate)
+func (c Cells) Intersect(other Cells) Cells {
+	intersection := make(Cells, 0)
+	for _, candidate := range c {
+		if other.Contains(candidate) {
+			intersection = append(intersection, candidate)
 		}
 	}
+	return intersection
+}
 
-	return difference
+// SortByCandidateCount orders unset cells from fewest candidates to most, as
+// wanted for picking the most constrained cell. Filled cells have no
+// candidates either, but are nothing to choose, so they go last.
+func (c Cells) SortByCandidateCount() {
+	sort.SliceStable(c, func(i, j int) bool {
+		return candidateRank(c[i]) < candidateRank(c[j])
+	})
+}
+
+func candidateRank(cell *Cell) int {
+	if cell.value != 0 {
+		return Size + 1
+	}
+	return cell.moves.Count()
+}
+
+// MinCandidateCell returns the first unset cell with the fewest candidates,
+// ignoring cells that have none left, or nil if there is no such cell.
+func (c Cells) MinCandidateCell() *Cell {
+	var best *Cell
+	for _, cell := range c {
+		if cell.value != 0 || cell.moves == empty {
+			continue
+		}
+		if best == nil || cell.moves.Count() < best.moves.Count() {
+			best = cell
+		}
+	}
+	return best
 }
 
 func (c Cells) LocationString() string {
@@ -114,12 +188,38 @@ func (c Cells) PowerSet() []Cells {
 	return sets
 }
 
-func (c Cells) RemainingMoves() *Moves {
+func (c Cells) RemainingMoves() Moves {
 	moves := empty
 	for _, cell := range c {
 		moves = moves | cell.moves
 	}
-	return &moves
+	return moves
+}
+
+func (c Cells) Values() []int {
+	values := make([]int, 0)
+	for _, cell := range c {
+		if cell.value != 0 {
+			values = append(values, cell.value)
+		}
+	}
+	return values
+}
+
+func (c Cells) ValueSet() Moves {
+	values := empty
+	for _, cell := range c {
+		if cell.value != 0 {
+			values.Add(cell.value)
+		}
+	}
+	return values
+}
+
+// MissingValues returns the values not yet placed in the cells. Unlike
+// RemainingMoves, it ignores the candidates of the unset cells.
+func (c Cells) MissingValues() Moves {
+	return full &^ c.ValueSet()
 }
 
 func (c Cells) EliminateMove(value int) int {
@@ -132,14 +232,33 @@ func (c Cells) EliminateMove(value int) int {
 	return changes
 }
 
+// TryEliminateMove is EliminateMove for values that come from user input: it
+// returns an error for a value out of range rather than panicking.
+func (c Cells) TryEliminateMove(value int) (int, error) {
+	if value < 1 || value > Size {
+		return 0, fmt.Errorf("Value out of range: %d", value)
+	}
+	return c.EliminateMove(value), nil
+}
+
+func (c Cells) EliminateMoves(mask Moves) int {
+	changes := 0
+	for _, cell := range c {
+		removed := cell.moves & mask
+		changes += removed.Count()
+		cell.moves &^= mask
+	}
+	return changes
+}
+
 func (c Cells) UniqueRows() []int {
-	rowsPresent := [9]bool{}
+	rowsPresent := [Size]bool{}
 	for _, cell := range c {
 		rowsPresent[cell.row] = true
 	}
 
 	rows := make([]int, 0)
-	for row := 0; row < 9; row++ {
+	for row := 0; row < Size; row++ {
 		if rowsPresent[row] {
 			rows = append(rows, row)
 		}
@@ -149,13 +268,13 @@ func (c Cells) UniqueRows() []int {
 }
 
 func (c Cells) UniqueCols() []int {
-	colsPresent := [9]bool{}
+	colsPresent := [Size]bool{}
 	for _, cell := range c {
 		colsPresent[cell.col] = true
 	}
 
 	cols := make([]int, 0)
-	for col := 0; col < 9; col++ {
+	for col := 0; col < Size; col++ {
 		if colsPresent[col] {
 			cols = append(cols, col)
 		}
@@ -0,0 +1,246 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+// movesOf builds a mask from values.
+func movesOf(values ...int) Moves {
+	m := empty
+	for _, value := range values {
+		m.Add(value)
+	}
+	return m
+}
+
+func TestCellsValues(t *testing.T) {
+	// The first row of medium.txt is "3 65 84  "
+	row := loadPuzzle(t, "medium.txt").Row(0)
+
+	if got, want := row.Values(), []int{3, 6, 5, 8, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+	if got, want := row.ValueSet(), movesOf(3, 4, 5, 6, 8); got != want {
+		t.Errorf("ValueSet() = %v, want %v", got.Slice(), want.Slice())
+	}
+	if got := row.UnsetOnly().Values(); len(got) != 0 {
+		t.Errorf("Unset cells have values %v", got)
+	}
+}
+
+func TestCellsContains(t *testing.T) {
+	s := blankSudoku(t)
+	row, col := s.Row(0), s.Col(0)
+
+	if !row.Contains(s.Cell(0, 0)) || !col.Contains(s.Cell(0, 0)) {
+		t.Error("Overlapping cell not found")
+	}
+	if row.Contains(s.Cell(1, 0)) {
+		t.Error("Row 1 contains a cell of row 2")
+	}
+	if (Cells{}).Contains(s.Cell(0, 0)) {
+		t.Error("Empty cells contain a cell")
+	}
+
+	// Cells match by position, so a clone's cell is found too
+	if !row.Contains(s.Clone().Cell(0, 4)) {
+		t.Error("Cell of a clone not found")
+	}
+}
+
+func TestCellsExcluding(t *testing.T) {
+	s := blankSudoku(t)
+
+	overlapping := s.Row(0).Excluding(s.Square(0, 0))
+	if len(overlapping) != Size-BoxSize || overlapping.Contains(s.Cell(0, 2)) || !overlapping.Contains(s.Cell(0, 3)) {
+		t.Errorf("Row 1 excluding its top left square gave %s", overlapping.LocationString())
+	}
+
+	disjoint := s.Row(0).Excluding(s.Row(1))
+	if len(disjoint) != Size {
+		t.Errorf("Row 1 excluding row 2 gave %s", disjoint.LocationString())
+	}
+}
+
+func TestCellsIntersect(t *testing.T) {
+	s := blankSudoku(t)
+
+	both := s.Row(4).Intersect(s.Square(1, 1))
+	if len(both) != BoxSize {
+		t.Fatalf("Row 5 and the center square share %s", both.LocationString())
+	}
+	for i, cell := range both {
+		if cell.row != 4 || cell.col != BoxSize+i {
+			t.Errorf("Unexpected cell (%d,%d)", cell.row+1, cell.col+1)
+		}
+	}
+
+	if none := s.Row(0).Intersect(s.Square(1, 1)); len(none) != 0 {
+		t.Errorf("Row 1 and the center square share %s", none.LocationString())
+	}
+}
+
+func TestSortByCandidateCount(t *testing.T) {
+	s, err := NewSudoku(gridOf(singleLine("1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := s.Row(0)
+	row[1].moves = movesOf(2, 3, 4)
+	row[2].moves = movesOf(5)
+	row[3].moves = movesOf(6, 7)
+	row[4].moves = movesOf(2, 3, 4, 5)
+
+	cells := row[:5]
+	cells.SortByCandidateCount()
+
+	want := []*Cell{s.Cell(0, 2), s.Cell(0, 3), s.Cell(0, 1), s.Cell(0, 4), s.Cell(0, 0)}
+	for i, cell := range cells {
+		if cell != want[i] {
+			t.Errorf("Position %d holds (%d,%d), want (%d,%d)", i+1, cell.row+1, cell.col+1, want[i].row+1, want[i].col+1)
+		}
+	}
+}
+
+func TestConjugatePair(t *testing.T) {
+	s := blankSudoku(t)
+	row := s.Row(0)
+	row[1:8].EliminateMove(4)
+
+	pair, ok := row.ConjugatePair(4)
+	if !ok || len(pair) != 2 || pair[0] != row[0] || pair[1] != row[8] {
+		t.Errorf("Got %s, %v; want the first and last cells", pair.LocationString(), ok)
+	}
+	if pair, ok := row.ConjugatePair(5); ok {
+		t.Errorf("5 can go in every cell but found the pair %s", pair.LocationString())
+	}
+}
+
+func TestEliminateMoves(t *testing.T) {
+	s := blankSudoku(t)
+	row := s.Row(0)
+	row[0].moves = movesOf(1, 2)
+	row[1].moves = movesOf(4, 5)
+
+	if got := row.EliminateMoves(movesOf(1, 2, 3)); got != 2+0+3*(Size-2) {
+		t.Errorf("Removed %d candidates", got)
+	}
+	if got := row[0].moves; got != empty {
+		t.Errorf("First cell left with %v", got.Slice())
+	}
+	if got := row[1].moves; got != movesOf(4, 5) {
+		t.Errorf("Second cell left with %v", got.Slice())
+	}
+	if got, want := row[2].moves, full&^movesOf(1, 2, 3); got != want {
+		t.Errorf("Third cell left with %v, want %v", got.Slice(), want.Slice())
+	}
+	if got := s.Cell(1, 0).moves; got != full {
+		t.Errorf("Cell outside the group left with %v", got.Slice())
+	}
+}
+
+func TestCellsFilter(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	few := s.Cells().Filter(func(cell *Cell) bool {
+		return cell.value == 0 && cell.moves.Count() <= 3
+	})
+	if len(few) == 0 {
+		t.Fatal("No cells with at most three candidates")
+	}
+	for _, cell := range s.Cells() {
+		want := cell.value == 0 && cell.moves.Count() <= 3
+		if few.Contains(cell) != want {
+			t.Errorf("(%d,%d) selected: %v, want %v", cell.row+1, cell.col+1, !want, want)
+		}
+	}
+
+	// FindMove is built on Filter. Row 1 column 2 is a 1 in the solution.
+	ones := s.Row(0).FindMove(1)
+	if !ones.Contains(s.Cell(0, 1)) {
+		t.Errorf("FindMove(1) returned %s", ones.LocationString())
+	}
+	for _, cell := range ones {
+		if !cell.moves.Contains(1) {
+			t.Errorf("FindMove(1) returned (%d,%d) without a 1", cell.row+1, cell.col+1)
+		}
+	}
+}
+
+func TestMissingValues(t *testing.T) {
+	// Row 3 of medium.txt is " 87    31"
+	row := loadPuzzle(t, "medium.txt").Row(2)
+	if got, want := row.MissingValues(), full&^movesOf(8, 7, 3, 1); got != want {
+		t.Errorf("Row 3 is missing %v, want %v", got.Slice(), want.Slice())
+	}
+
+	square := blankSudoku(t).Square(0, 0)
+	for i, value := range []int{1, 2, 3, 4, 5, 6} {
+		square[i].value = value
+		square[i].moves = empty
+	}
+	if got, want := square.MissingValues(), movesOf(7, 8, 9); got != want {
+		t.Errorf("Square is missing %v, want %v", got.Slice(), want.Slice())
+	}
+}
+
+func TestRemainingMovesIsACopy(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	row := s.Row(0)
+	before := row[1].moves
+
+	remaining := row.RemainingMoves()
+	if remaining&before != before {
+		t.Errorf("Remaining %v does not cover (1,2) with %v", remaining.Slice(), before.Slice())
+	}
+	remaining.Remove(before.Slice()[0])
+	if row[1].moves != before || row.RemainingMoves()&before != before {
+		t.Error("Changing the result changed the cells")
+	}
+}
+
+func TestPositionMask(t *testing.T) {
+	s := blankSudoku(t)
+	row := s.Row(3)
+	row.Excluding(Cells{row[2], row[7]}).EliminateMove(6)
+
+	if got, want := row.PositionMask(6), uint(1<<2|1<<7); got != want {
+		t.Errorf("Got %09b, want %09b", got, want)
+	}
+	if got, want := row.PositionMask(5), uint(1<<Size-1); got != want {
+		t.Errorf("Got %09b for an open value, want %09b", got, want)
+	}
+}
+
+func TestMinCandidateCell(t *testing.T) {
+	s := blankSudoku(t)
+	row := s.Row(0)
+	row[0].value, row[0].moves = 4, empty
+	row[1].moves = empty
+	row[2].moves = movesOf(1, 2, 3)
+	row[3].moves = movesOf(5, 6)
+	row[4].moves = movesOf(7, 8)
+
+	if got := row.MinCandidateCell(); got != row[3] {
+		t.Errorf("Got %s, want the first cell with two candidates", Cells{got}.LocationString())
+	}
+	if got := row[:2].MinCandidateCell(); got != nil {
+		t.Errorf("Got %s from a filled cell and a dead one", Cells{got}.LocationString())
+	}
+}
+
+func TestTryEliminateMove(t *testing.T) {
+	row := blankSudoku(t).Row(0)
+	for _, value := range []int{0, Size + 1} {
+		if _, err := row.TryEliminateMove(value); err == nil {
+			t.Errorf("Value %d accepted", value)
+		}
+	}
+	if got := row[0].moves; got != full {
+		t.Errorf("Rejected values left %v", got.Slice())
+	}
+
+	if got, err := row.TryEliminateMove(4); got != Size || err != nil {
+		t.Errorf("Got %d, %v; want %d removed", got, err, Size)
+	}
+}
@@ -0,0 +1,61 @@
+package internal
+
+// Longest XY-chain searched for, in cells. Longer chains are rare and the
+// search grows quickly with length.
+const maxXYChainLength = 6
+
+// An XY-chain is a sequence of bivalue cells, each seeing the next, where
+// neighbours share a candidate. If the first cell is not z, its other
+// candidate is forced into the next cell and so on down the chain, until the
+// last cell is z. Either way one end of the chain is z, so z can be eliminated
+// from every cell that sees both ends. Shorter chains are found first; the
+// three-cell chain is the Y-wing.
+func (s *Sudoku) xyChains() int {
+	bivalue := s.BiValueCells()
+	for length := 3; length <= maxXYChainLength; length++ {
+		for _, start := range bivalue {
+			for _, z := range start.Moves() {
+				if s.extendXYChain(Cells{start}, z, otherCandidate(start, z), length, bivalue) {
+					return 1
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// extendXYChain grows the chain to the given length through cells holding the
+// link candidate, and makes the elimination if the chain closes on z.
+func (s *Sudoku) extendXYChain(chain Cells, z, link, length int, bivalue Cells) bool {
+	last := chain[len(chain)-1]
+	if len(chain) == length {
+		if link != z {
+			return false
+		}
+		targets := s.Cells().UnsetOnly().Excluding(chain).Filter(func(cell *Cell) bool {
+			return cell.CanPlay(z) && sees(cell, chain[0]) && sees(cell, last)
+		})
+		if len(targets) == 0 {
+			return false
+		}
+		s.eliminateStep("XY-chain", targets, z, "The %d can be eliminated from cells %s since one end of the XY-chain %s must be %d", z, targets.LocationString(), chain.LocationString(), z)
+		return true
+	}
+
+	for _, next := range bivalue {
+		if !next.CanPlay(link) || !sees(last, next) || chain.Contains(next) {
+			continue
+		}
+		extended := append(chain[:len(chain):len(chain)], next)
+		if s.extendXYChain(extended, z, otherCandidate(next, link), length, bivalue) {
+			return true
+		}
+	}
+	return false
+}
+
+func otherCandidate(cell *Cell, value int) int {
+	rest := cell.moves
+	rest.Remove(value)
+	return rest.Slice()[0]
+}
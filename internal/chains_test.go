@@ -0,0 +1,54 @@
+package internal
+
+import "testing"
+
+func TestXYChain(t *testing.T) {
+	// 12 at (1,1), 23 at (1,5), 34 at (5,5) and 41 at (5,2): one end is a 1
+	s := blankSudoku(t)
+	s.Cell(0, 0).moves = movesOf(1, 2)
+	s.Cell(0, 4).moves = movesOf(2, 3)
+	s.Cell(4, 4).moves = movesOf(3, 4)
+	s.Cell(4, 1).moves = movesOf(4, 1)
+
+	if got := s.xyChains(); got != 1 {
+		t.Fatalf("Found %d chains, want 1", got)
+	}
+	steps := s.Steps()
+	if len(steps) != 1 || steps[0].Technique != "XY-chain" || steps[0].Value != 1 {
+		t.Fatalf("Got steps %v", steps)
+	}
+
+	for _, cell := range (Cells{s.Cell(0, 1), s.Cell(1, 1), s.Cell(4, 0), s.Cell(5, 0)}) {
+		if cell.moves.Contains(1) {
+			t.Errorf("1 left in (%d,%d), which sees both ends", cell.row+1, cell.col+1)
+		}
+	}
+	for _, cell := range (Cells{s.Cell(0, 2), s.Cell(4, 3), s.Cell(0, 0), s.Cell(4, 1)}) {
+		if !cell.moves.Contains(1) {
+			t.Errorf("1 removed from (%d,%d)", cell.row+1, cell.col+1)
+		}
+	}
+}
+
+func TestXYChainMustClose(t *testing.T) {
+	// The last cell is 45, so neither end is forced to be 1
+	s := blankSudoku(t)
+	s.Cell(0, 0).moves = movesOf(1, 2)
+	s.Cell(0, 4).moves = movesOf(2, 3)
+	s.Cell(4, 4).moves = movesOf(3, 4)
+	s.Cell(4, 1).moves = movesOf(4, 5)
+
+	if got := s.xyChains(); got != 0 {
+		t.Errorf("Found a chain: %v", s.Steps())
+	}
+}
+
+func TestXYChainSolvesExpert1(t *testing.T) {
+	s := loadPuzzle(t, "expert1.txt")
+	if s.solvableWith(TierSubsets) {
+		t.Fatal("expert1.txt solved without chains")
+	}
+	if err := s.SolveLogicalOnly(TierChains); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,49 @@
+package internal
+
+import "sync"
+
+// A Sudoku is not safe for concurrent use by default. EnableConcurrentReads
+// makes the solver take a write lock around each mutation, so that another
+// goroutine can take consistent snapshots with ValueSafe and GridSafe while a
+// solve is running. Other accessors remain unguarded.
+func (s *Sudoku) EnableConcurrentReads() {
+	if s.mu == nil {
+		s.mu = &sync.RWMutex{}
+	}
+}
+
+func (s *Sudoku) ValueSafe(row, col int) int {
+	s.rlock()
+	defer s.runlock()
+	return s.board[row][col].value
+}
+
+func (s *Sudoku) GridSafe() [Size][Size]int {
+	s.rlock()
+	defer s.runlock()
+	return s.Grid()
+}
+
+func (s *Sudoku) lock() {
+	if s.mu != nil {
+		s.mu.Lock()
+	}
+}
+
+func (s *Sudoku) unlock() {
+	if s.mu != nil {
+		s.mu.Unlock()
+	}
+}
+
+func (s *Sudoku) rlock() {
+	if s.mu != nil {
+		s.mu.RLock()
+	}
+}
+
+func (s *Sudoku) runlock() {
+	if s.mu != nil {
+		s.mu.RUnlock()
+	}
+}
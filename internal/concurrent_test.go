@@ -0,0 +1,40 @@
+package internal
+
+import "testing"
+
+// Run with -race to check the locking.
+func TestConcurrentReads(t *testing.T) {
+	s := loadPuzzle(t, "expert1.txt")
+	s.EnableConcurrentReads()
+
+	done := make(chan error)
+	go func() {
+		done <- s.Solve()
+	}()
+
+	var snapshots [][Size][Size]int
+	for solving := true; solving; {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			solving = false
+		default:
+			snapshots = append(snapshots, s.GridSafe())
+			s.ValueSafe(4, 4)
+		}
+	}
+
+	// Values are only ever placed, so every snapshot is part of the solution
+	solution := s.Grid()
+	for _, grid := range snapshots {
+		for row := 0; row < Size; row++ {
+			for col := 0; col < Size; col++ {
+				if value := grid[row][col]; value != 0 && value != solution[row][col] {
+					t.Fatalf("Snapshot has %d at (%d,%d), solution has %d", value, row+1, col+1, solution[row][col])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package internal
+
+// CandidateChange describes how the candidates of one cell differ between two
+// boards. Rows and columns count from zero.
+type CandidateChange struct {
+	Row     int
+	Col     int
+	Added   Moves
+	Removed Moves
+}
+
+// DiffCandidates lists the cells whose candidates differ between before and
+// after, in row-major order. Comparing a board with a clone that a technique
+// was applied to shows exactly what the technique eliminated.
+func DiffCandidates(before, after *Sudoku) []CandidateChange {
+	changes := make([]CandidateChange, 0)
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
+			was, now := before.board[row][col].moves, after.board[row][col].moves
+			if was == now {
+				continue
+			}
+			changes = append(changes, CandidateChange{
+				Row:     row,
+				Col:     col,
+				Added:   now &^ was,
+				Removed: was &^ now,
+			})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffCandidates(t *testing.T) {
+	// The solver has no X-Wing, so a pointing pair stands in: the 1 of the
+	// top left square can only go in its top row
+	before := blankSudoku(t)
+	before.Square(0, 0).Excluding(before.Row(0)).EliminateMove(1)
+	after := before.Clone()
+	if after.pointingPairs() != 1 {
+		t.Fatal("No pointing pair")
+	}
+
+	var want []CandidateChange
+	for col := BoxSize; col < Size; col++ {
+		want = append(want, CandidateChange{Row: 0, Col: col, Removed: movesOf(1)})
+	}
+	if got := DiffCandidates(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+
+	// Reversed, the same candidates are added back
+	for _, change := range DiffCandidates(after, before) {
+		if change.Added != movesOf(1) || change.Removed != empty {
+			t.Errorf("Reversed diff has %+v", change)
+		}
+	}
+	if got := DiffCandidates(before, before); len(got) != 0 {
+		t.Errorf("A board differs from itself: %+v", got)
+	}
+}
@@ -0,0 +1,21 @@
+package internal
+
+type CellState struct {
+	Value      int   `json:"value"`
+	Candidates []int `json:"candidates"`
+	Given      bool  `json:"given"`
+}
+
+type EditorState [Size][Size]CellState
+
+func (s *Sudoku) EditorState() EditorState {
+	state := EditorState{}
+	for _, cell := range s.Cells() {
+		state[cell.row][cell.col] = CellState{
+			Value:      cell.value,
+			Candidates: cell.Moves(),
+			Given:      cell.given,
+		}
+	}
+	return state
+}
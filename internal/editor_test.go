@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditorState(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if err := s.PlayMove(0, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	edited := s.Cell(1, 2)
+	edited.moves = movesOf(4, 9)
+
+	state := s.EditorState()
+	if got, want := state[0][0], (CellState{Value: 3, Candidates: []int{}, Given: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Given cell is %+v, want %+v", got, want)
+	}
+	if got := state[0][1]; got.Value != 1 || got.Given || len(got.Candidates) != 0 {
+		t.Errorf("Placed cell is %+v", got)
+	}
+	if got := state[1][2]; got.Value != 0 || got.Given || !reflect.DeepEqual(got.Candidates, []int{4, 9}) {
+		t.Errorf("Edited cell is %+v", got)
+	}
+}
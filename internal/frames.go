@@ -0,0 +1,18 @@
+package internal
+
+// SolveFrames solves the puzzle and returns a snapshot of the grid after each
+// placement, so a client can scrub through the solve. Eliminations leave the
+// grid unchanged and add no frames. On error the frames up to the point the
+// solve stopped are still returned.
+func (s *Sudoku) SolveFrames() ([][Size][Size]int, error) {
+	frames := make([][Size][Size]int, 0)
+	s.onStep = func(step Step) {
+		if step.Action == Place {
+			frames = append(frames, s.Grid())
+		}
+	}
+	defer func() { s.onStep = nil }()
+
+	err := s.Solve()
+	return frames, err
+}
@@ -0,0 +1,52 @@
+package internal
+
+import "testing"
+
+func TestSolveFrames(t *testing.T) {
+	s := loadPuzzle(t, "hard1.txt")
+	blanks := len(s.Cells().UnsetOnly())
+	previous := s.Grid()
+
+	frames, err := s.SolveFrames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != blanks {
+		t.Fatalf("Got %d frames, want one for each of %d placements", len(frames), blanks)
+	}
+
+	// Each frame fills exactly the cell of its step
+	placements := make([]Step, 0)
+	for _, step := range s.Steps() {
+		if step.Action == Place {
+			placements = append(placements, step)
+		}
+	}
+	for i, frame := range frames {
+		cell := placements[i].Cells[0]
+		want := previous
+		want[cell.row][cell.col] = placements[i].Value
+		if frame != want {
+			t.Errorf("Frame %d is\n%v\nwant (%d,%d) set to %d", i+1, frame, cell.row+1, cell.col+1, placements[i].Value)
+		}
+		previous = frame
+	}
+	if previous != s.Grid() {
+		t.Error("Last frame is not the solution")
+	}
+}
+
+func TestSolveFramesStuck(t *testing.T) {
+	s := loadPuzzle(t, "expert3.txt")
+	before := len(s.Cells().UnsetOnly())
+	frames, err := s.SolveFrames()
+	if err == nil {
+		t.Fatal("expert3.txt solved without guessing")
+	}
+	if got, want := len(frames), before-len(s.Cells().UnsetOnly()); got != want {
+		t.Errorf("Got %d frames, want %d", got, want)
+	}
+	if s.onStep != nil {
+		t.Error("SolveFrames left its hook behind")
+	}
+}
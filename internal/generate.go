@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+type GenerateOptions struct {
+	// The hardest techniques a player may need. Puzzles are only accepted if
+	// SolveLogicalOnly solves them with techniques up to this tier.
+	MaxTier Tier
+
+	// Clues are removed until this many remain, or until no more can be
+	// removed. Zero removes as many as possible.
+	Clues int
+
+	// Source of randomness. A time-seeded source is used if nil.
+	Rand *rand.Rand
+}
+
+func Generate(opts GenerateOptions) (*Sudoku, error) {
+	r := opts.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	solution, err := NewSudoku([Size][Size]int{})
+	if err != nil {
+		return nil, err
+	}
+	if !solution.fillRandomly(r) {
+		return nil, errors.New("Could not generate a solved board")
+	}
+
+	grid := solution.Grid()
+	clues := cellCount
+	for _, i := range r.Perm(cellCount) {
+		if clues <= opts.Clues {
+			break
+		}
+
+		candidate := grid
+		candidate[i/Size][i%Size] = 0
+		puzzle, err := NewSudoku(candidate)
+		if err != nil || puzzle.CountSolutions(2) != 1 || !puzzle.solvableWith(opts.MaxTier) {
+			continue
+		}
+
+		grid = candidate
+		clues--
+	}
+
+	return NewSudoku(grid)
+}
+
+func (s *Sudoku) solvableWith(max Tier) bool {
+	clone := s.quietClone()
+	return clone.SolveLogicalOnly(max) == nil
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestGenerateSinglesOnly(t *testing.T) {
+	s, err := Generate(GenerateOptions{MaxTier: TierSingles, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetOutput(io.Discard)
+
+	if got := s.CountSolutions(2); got != 1 {
+		t.Fatalf("Generated a puzzle with %d solutions", got)
+	}
+	if err := s.SolveLogicalOnly(TierSingles); err != nil {
+		t.Fatalf("Generated puzzle needs more than singles: %v\n%v", err, s)
+	}
+	for _, step := range s.Steps() {
+		if step.Technique != "naked single" && step.Technique != "hidden single" {
+			t.Errorf("Solved with %s", step.Technique)
+		}
+	}
+}
+
+func TestSolveLogicalOnlyStopsAtTier(t *testing.T) {
+	s := loadPuzzle(t, "expert1.txt")
+	if err := s.SolveLogicalOnly(TierSingles); err == nil {
+		t.Error("expert1.txt solved with singles")
+	}
+	if s.IsSolved() {
+		t.Error("SolveLogicalOnly guessed")
+	}
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotResolvable = errors.New("Cell cannot be resolved logically yet")
+
+func (s *Sudoku) Explain(row, col int) (int, string, error) {
+	if row < 0 || row >= Size || col < 0 || col >= Size {
+		return 0, "", fmt.Errorf("Cell %d,%d out of bounds", row+1, col+1)
+	}
+
+	cell := s.Cell(row, col)
+	if cell.value != 0 {
+		return 0, "", fmt.Errorf("Cell %d,%d already contains %d", row+1, col+1, cell.value)
+	}
+
+	if moves := cell.Moves(); len(moves) == 1 {
+		return moves[0], "naked single", nil
+	}
+
+	for _, group := range []Cells{s.Square(row/BoxSize, col/BoxSize), s.Row(row), s.Col(col)} {
+		for _, value := range cell.Moves() {
+			if len(group.FindMove(value)) == 1 {
+				return value, "hidden single", nil
+			}
+		}
+	}
+
+	return 0, "", ErrNotResolvable
+}
+
+// ClassifyNextPlacement finds the next cell that can be filled by a single,
+// without changing the board. The kind is "elimination" when every other
+// value is ruled out of the cell (a naked single) or "position" when the value
+// fits nowhere else in a row, column or square (a hidden single).
+func (s *Sudoku) ClassifyNextPlacement() (row, col, value int, kind string, err error) {
+	if len(s.Cells().UnsetOnly()) == 0 {
+		return 0, 0, 0, "", errors.New("Puzzle is already solved")
+	}
+
+	singles := []struct {
+		kind  string
+		apply func(*Sudoku) (int, error)
+	}{
+		{"elimination", (*Sudoku).nakedSingles},
+		{"position", (*Sudoku).hiddenSingles},
+	}
+
+	for _, single := range singles {
+		clone := s.quietClone()
+		changes, err := single.apply(clone)
+		if err != nil {
+			return 0, 0, 0, "", err
+		}
+		if changes > 0 {
+			step := clone.steps[0]
+			return step.Cells[0].row, step.Cells[0].col, step.Value, single.kind, nil
+		}
+	}
+
+	return 0, 0, 0, "", ErrNoTechnique
+}
+
+// IsEliminationSafe reports whether value can be struck from the candidates
+// of a cell without ruling out the solution. It needs the puzzle to have a
+// unique solution, which it finds by search.
+func (s *Sudoku) IsEliminationSafe(row, col, value int) (bool, error) {
+	if row < 0 || row >= Size || col < 0 || col >= Size {
+		return false, fmt.Errorf("Cell %d,%d out of bounds", row+1, col+1)
+	}
+	if value < 1 || value > Size {
+		return false, fmt.Errorf("Value %d out of bounds", value)
+	}
+	if cell := s.Cell(row, col); cell.value != 0 {
+		return false, fmt.Errorf("Cell %d,%d already contains %d", row+1, col+1, cell.value)
+	}
+
+	solutions, err := s.Solutions(2)
+	if err != nil {
+		return false, err
+	}
+	switch len(solutions) {
+	case 0:
+		return false, ErrNoSolution
+	case 1:
+		return solutions[0].board[row][col].value != value, nil
+	default:
+		return false, ErrMultipleSolutions
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import "testing"
+
+func TestExplainNakedSingle(t *testing.T) {
+	s := blankSudoku(t)
+	s.Cell(0, 0).moves = movesOf(5)
+
+	value, technique, err := s.Explain(0, 0)
+	if value != 5 || technique != "naked single" || err != nil {
+		t.Errorf("Got %d, %q, %v; want 5, naked single", value, technique, err)
+	}
+}
+
+func TestExplainHiddenSingle(t *testing.T) {
+	s := blankSudoku(t)
+	s.Square(1, 1).Excluding(Cells{s.Cell(4, 4)}).EliminateMove(7)
+
+	value, technique, err := s.Explain(4, 4)
+	if value != 7 || technique != "hidden single" || err != nil {
+		t.Errorf("Got %d, %q, %v; want 7, hidden single", value, technique, err)
+	}
+	if got := s.Cell(4, 4).moves.Count(); got != Size {
+		t.Errorf("Explain changed the cell to %d candidates", got)
+	}
+}
+
+func TestExplainUnresolved(t *testing.T) {
+	s := blankSudoku(t)
+	if _, _, err := s.Explain(0, 0); err != ErrNotResolvable {
+		t.Errorf("Got error %v, want %v", err, ErrNotResolvable)
+	}
+	if _, _, err := s.Explain(Size, 0); err == nil {
+		t.Error("Cell out of bounds accepted")
+	}
+
+	s = loadPuzzle(t, "medium.txt")
+	if _, _, err := s.Explain(0, 0); err == nil {
+		t.Error("Filled cell explained")
+	}
+}
+
+func TestClassifyNextPlacement(t *testing.T) {
+	s := blankSudoku(t)
+	s.Cell(2, 3).moves = movesOf(6)
+
+	row, col, value, kind, err := s.ClassifyNextPlacement()
+	if row != 2 || col != 3 || value != 6 || kind != "elimination" || err != nil {
+		t.Errorf("Got (%d,%d) %d by %q, %v; want (2,3) 6 by elimination", row, col, value, kind, err)
+	}
+
+	s = blankSudoku(t)
+	s.Square(1, 1).Excluding(Cells{s.Cell(4, 4)}).EliminateMove(7)
+
+	row, col, value, kind, err = s.ClassifyNextPlacement()
+	if row != 4 || col != 4 || value != 7 || kind != "position" || err != nil {
+		t.Errorf("Got (%d,%d) %d by %q, %v; want (4,4) 7 by position", row, col, value, kind, err)
+	}
+	if s.Cell(4, 4).value != 0 {
+		t.Error("ClassifyNextPlacement changed the board")
+	}
+}
+
+func TestClassifyNextPlacementStuck(t *testing.T) {
+	if _, _, _, _, err := blankSudoku(t).ClassifyNextPlacement(); err != ErrNoTechnique {
+		t.Errorf("Got error %v, want %v", err, ErrNoTechnique)
+	}
+}
+
+func TestIsEliminationSafe(t *testing.T) {
+	// The solution of medium.txt has a 1 in row 1 column 2
+	s := loadPuzzle(t, "medium.txt")
+	if safe, err := s.IsEliminationSafe(0, 1, 1); safe || err != nil {
+		t.Errorf("Striking the solution: got %v, %v; want unsafe", safe, err)
+	}
+	if safe, err := s.IsEliminationSafe(0, 1, 9); !safe || err != nil {
+		t.Errorf("Striking a wrong candidate: got %v, %v; want safe", safe, err)
+	}
+	if !s.Cell(0, 1).moves.Contains(1) || s.Cell(0, 1).value != 0 {
+		t.Error("IsEliminationSafe changed the board")
+	}
+}
+
+func TestIsEliminationSafeErrors(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	for name, args := range map[string][3]int{
+		"cell out of bounds":  {Size, 0, 1},
+		"value out of bounds": {0, 1, 0},
+		"filled cell":         {0, 0, 3},
+	} {
+		if _, err := s.IsEliminationSafe(args[0], args[1], args[2]); err == nil {
+			t.Errorf("Accepted a %s", name)
+		}
+	}
+	if _, err := loadPuzzle(t, "blank.txt").IsEliminationSafe(0, 0, 1); err != ErrMultipleSolutions {
+		t.Errorf("Got error %v, want %v", err, ErrMultipleSolutions)
+	}
+}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func (s *Sudoku) WriteCandidatesJSON(w io.Writer) error {
+	candidates := make([][][]int, Size)
+	for row := 0; row < Size; row++ {
+		candidates[row] = make([][]int, Size)
+		for col := 0; col < Size; col++ {
+			candidates[row][col] = s.Cell(row, col).Moves()
+		}
+	}
+	return json.NewEncoder(w).Encode(candidates)
+}
+
+// MarshalJSON encodes the board as nine rows of nine values, with 0 for blank
+// cells.
+func (s *Sudoku) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Grid())
+}
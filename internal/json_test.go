@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteCandidatesJSON(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+
+	var out strings.Builder
+	if err := s.WriteCandidatesJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nine rows of nine cells, each an array of values
+	var raw [][]json.RawMessage
+	if err := json.Unmarshal([]byte(out.String()), &raw); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", out.String(), err)
+	}
+	if len(raw) != Size {
+		t.Fatalf("Got %d rows, want %d", len(raw), Size)
+	}
+	for row, cells := range raw {
+		if len(cells) != Size {
+			t.Fatalf("Row %d has %d cells, want %d", row+1, len(cells), Size)
+		}
+		for col, text := range cells {
+			var candidates []int
+			if err := json.Unmarshal(text, &candidates); err != nil || candidates == nil {
+				t.Fatalf("Cell (%d,%d) is %s, want an array", row+1, col+1, text)
+			}
+			if want := s.Cell(row, col).Moves(); !reflect.DeepEqual(candidates, want) {
+				t.Errorf("Cell (%d,%d) has candidates %v, want %v", row+1, col+1, candidates, want)
+			}
+		}
+	}
+
+	// The first cell is a given
+	if string(raw[0][0]) != "[]" {
+		t.Errorf("Solved cell encoded as %s, want []", raw[0][0])
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var grid [Size][Size]int
+	if err := json.Unmarshal(data, &grid); err != nil {
+		t.Fatal(err)
+	}
+	if grid != s.Grid() {
+		t.Errorf("Got %s", data)
+	}
+}
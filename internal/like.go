@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"io"
+	"strings"
+)
+
+// WriteLike writes the board using original, the text the puzzle was read
+// from, as a template. Separators, spacing and line breaks are copied as they
+// are, and each cell of the original is replaced with the current value of the
+// board. Cells that are still blank keep the blank character of the original.
+// Single-line puzzles and plain grids are supported.
+func (s *Sudoku) WriteLike(w io.Writer, original string) {
+	lines := strings.Split(original, "\n")
+
+	var text strings.Builder
+	if isSingleLine(firstLine(lines)) {
+		s.writeSingleLineLike(&text, lines)
+	} else {
+		s.writeGridLike(&text, lines)
+	}
+	io.WriteString(w, text.String())
+}
+
+func firstLine(lines []string) string {
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func (s *Sudoku) writeSingleLineLike(text *strings.Builder, lines []string) {
+	i := 0
+	for n, line := range lines {
+		if n > 0 {
+			text.WriteByte('\n')
+		}
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			if i < cellCount && (c == '.' || (c >= '0' && c <= '9')) {
+				c = s.cellByte(i/Size, i%Size, c)
+				i++
+			}
+			text.WriteByte(c)
+		}
+	}
+}
+
+func (s *Sudoku) writeGridLike(text *strings.Builder, lines []string) {
+	row := 0
+	for n, line := range lines {
+		if n > 0 {
+			text.WriteByte('\n')
+		}
+
+		body := strings.TrimSuffix(line, "\r")
+		col := 0
+		for j := 0; j < len(body); j++ {
+			c := body[j]
+			if row < Size && col < Size && isPlainCell(c) {
+				c = s.cellByte(row, col, c)
+				col++
+			}
+			text.WriteByte(c)
+		}
+
+		// Trailing blanks are often trimmed from grid rows
+		if col > 0 {
+			for ; col < Size; col++ {
+				text.WriteByte(s.cellByte(row, col, ' '))
+			}
+			row++
+		}
+		text.WriteString(line[len(body):])
+	}
+}
+
+func (s *Sudoku) cellByte(row, col int, blank byte) byte {
+	if value := s.board[row][col].value; value != 0 {
+		return byte('0' + value)
+	}
+	return blank
+}
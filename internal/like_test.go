@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteLikeGrid(t *testing.T) {
+	original, err := os.ReadFile(filepath.Join("..", "puzzles", "hard1.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := loadPuzzle(t, "hard1.txt")
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	s.WriteLike(&out, string(original))
+
+	got, want := strings.Split(out.String(), "\n"), strings.Split(string(original), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("Got %d lines, want %d:\n%s", len(got), len(want), out.String())
+	}
+	for i := range want {
+		if strings.Count(got[i], "|") != strings.Count(want[i], "|") || strings.HasPrefix(want[i], "-") != strings.HasPrefix(got[i], "-") {
+			t.Errorf("Line %d is %q, want the layout of %q", i+1, got[i], want[i])
+		}
+	}
+	if reread := loadString(t, out.String()); reread.Grid() != s.Grid() {
+		t.Errorf("Read back\n%v", reread)
+	}
+}
+
+func TestWriteLikeSingleLine(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	original := s.String() + "\n"
+
+	var out strings.Builder
+	s.WriteLike(&out, original)
+	if out.String() != original {
+		t.Errorf("Unsolved puzzle written as %q, want %q", out.String(), original)
+	}
+
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	s.WriteLike(&out, original)
+	if want := s.String() + "\n"; out.String() != want {
+		t.Errorf("Solution written as %q, want %q", out.String(), want)
+	}
+}
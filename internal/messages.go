@@ -0,0 +1,20 @@
+package internal
+
+// Messages translates the text the solver writes: board headings, warnings,
+// step messages and the names of positions such as "top left". Keys are the
+// English text as written in the source, including any format verbs, and
+// values replace it. Translations can reorder arguments with explicit indexes
+// such as %[2]d. Text without an entry stays in English. Errors and technique
+// names are not translated.
+type Messages map[string]string
+
+func (s *Sudoku) SetMessages(messages Messages) {
+	s.messages = messages
+}
+
+func (s *Sudoku) tr(text string) string {
+	if translated, ok := s.messages[text]; ok {
+		return translated
+	}
+	return text
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetMessages(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	s.SetMessages(Messages{
+		"Solved": "Gelöst",
+		"The %d in column %d only fits at row %d": "Spalte %[2]d: die %[1]d passt nur in Zeile %[3]d",
+	})
+	var out strings.Builder
+	s.SetOutput(&out)
+
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Gelöst\n") || strings.Contains(out.String(), "Solved") {
+		t.Errorf("Heading not translated:\n%s", out.String())
+	}
+
+	translated := 0
+	for _, step := range s.Steps() {
+		if !strings.HasPrefix(step.Message, "Spalte ") {
+			continue
+		}
+		cell := step.Cells[0]
+		if want := fmt.Sprintf("Spalte %d: die %d passt nur in Zeile %d", cell.col+1, step.Value, cell.row+1); step.Message != want {
+			t.Errorf("Got %q, want %q", step.Message, want)
+		}
+		translated++
+	}
+	if translated == 0 {
+		t.Error("No step translated")
+	}
+}
+
+func TestMessagesTranslatePositionNames(t *testing.T) {
+	// The 1 of the top left square can only go in its top row
+	s := blankSudoku(t)
+	s.SetMessages(Messages{
+		"top left": "oben links",
+		"top":      "oberen",
+		"In the %s square, the number %d only fits in the %s row": "Im Quadrat %s passt die %d nur in die %s Zeile",
+	})
+	s.Square(0, 0).Excluding(s.Row(0)).EliminateMove(1)
+	if s.pointingPairs() != 1 {
+		t.Fatal("No pointing pair")
+	}
+
+	if got, want := s.Steps()[0].Message, "Im Quadrat oben links passt die 1 nur in die oberen Zeile"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
+
+func TestMessagesDefaultToEnglish(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	s.SetMessages(Messages{"Round %d": "Runde %d"})
+	var out strings.Builder
+	s.SetOutput(&out)
+
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Solved\n") {
+		t.Errorf("Untranslated heading missing:\n%s", out.String())
+	}
+}
@@ -0,0 +1,86 @@
+package internal
+
+// PropagationDepth counts the rounds of techniques needed to solve the
+// puzzle, using the uniqueness techniques when the solution is unique.
+func (s *Sudoku) PropagationDepth() int {
+	clone := s.quietClone()
+	clone.solve(clone.safeTier())
+	return clone.rounds
+}
+
+func (s *Sudoku) SolveLength() (int, error) {
+	clone := s.quietClone()
+	if err := clone.Solve(); err != nil {
+		return len(clone.steps), err
+	}
+	return len(clone.steps), nil
+}
+
+// SearchNodes counts the guesses backtracking makes to finish the puzzle once
+// the logical techniques are stuck, as a rough measure of difficulty. Puzzles
+// solved by logic alone return 0.
+func (s *Sudoku) SearchNodes() (int, error) {
+	clone := s.quietClone()
+	err := clone.solve(clone.maxTier())
+	if err == nil {
+		return 0, nil
+	}
+	if err != ErrNoSolution {
+		return 0, err
+	}
+
+	nodes := 0
+	if !clone.search(&nodes) {
+		return nodes, ErrNoSolution
+	}
+	return nodes, nil
+}
+
+func (s *Sudoku) search(nodes *int) bool {
+	branch := s.branchCell()
+	if branch == nil {
+		return true
+	}
+
+	for _, value := range branch.Moves() {
+		clone := s.Clone()
+		if err := clone.PlayMove(branch.row, branch.col, value); err != nil {
+			continue
+		}
+		*nodes++
+		if clone.search(nodes) {
+			return true
+		}
+	}
+	return false
+}
+
+// NakedSingleCascade counts the cells that can be filled using naked singles
+// alone, repeating until none are left. Trivial puzzles fill most of the board
+// this way.
+func (s *Sudoku) NakedSingleCascade() int {
+	clone := s.quietClone()
+
+	placed := 0
+	for {
+		changes, err := clone.nakedSingles()
+		placed += changes
+		if err != nil || changes == 0 {
+			return placed
+		}
+	}
+}
+
+// TechniqueStats tallies the changes each technique made in the steps taken
+// so far: one for each placement, and one for each candidate eliminated.
+func (s *Sudoku) TechniqueStats() map[string]int {
+	stats := make(map[string]int)
+	for _, step := range s.steps {
+		if step.Action == Place {
+			stats[step.Technique]++
+		} else {
+			stats[step.Technique] += len(step.Cells)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,117 @@
+package internal
+
+import "testing"
+
+func TestPropagationDepth(t *testing.T) {
+	easy := loadPuzzle(t, "medium.txt")
+	hard := loadPuzzle(t, "expert1.txt")
+
+	easyDepth, hardDepth := easy.PropagationDepth(), hard.PropagationDepth()
+	if easyDepth < 1 || easyDepth >= hardDepth {
+		t.Errorf("Depths %d for medium.txt and %d for expert1.txt, want the easy one lower", easyDepth, hardDepth)
+	}
+	if len(hard.steps) != 0 || hard.rounds != 0 {
+		t.Error("PropagationDepth changed the puzzle")
+	}
+}
+
+func TestSolveLength(t *testing.T) {
+	// Both need subsets, but not chains
+	short, long := loadPuzzle(t, "expert2.txt"), loadPuzzle(t, "hard2.txt")
+	for _, s := range []*Sudoku{short, long} {
+		if s.solvableWith(TierIntersections) || !s.solvableWith(TierSubsets) {
+			t.Fatal("Puzzle not rated subsets")
+		}
+	}
+
+	shortLength, err := short.SolveLength()
+	if err != nil {
+		t.Fatal(err)
+	}
+	longLength, err := long.SolveLength()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shortLength >= longLength {
+		t.Errorf("Lengths %d for expert2.txt and %d for hard2.txt, want expert2.txt shorter", shortLength, longLength)
+	}
+	if blanks := len(short.Cells().UnsetOnly()); shortLength < blanks {
+		t.Errorf("Length %d is less than the %d blank cells", shortLength, blanks)
+	}
+}
+
+func TestSearchNodes(t *testing.T) {
+	if nodes, err := loadPuzzle(t, "expert1.txt").SearchNodes(); nodes != 0 || err != nil {
+		t.Errorf("Logical puzzle took %d nodes, %v", nodes, err)
+	}
+
+	s := loadPuzzle(t, "expert3.txt")
+	nodes, err := s.SearchNodes()
+	if nodes == 0 || err != nil {
+		t.Errorf("Puzzle needing guesses took %d nodes, %v", nodes, err)
+	}
+	if s.IsSolved() || len(s.steps) != 0 {
+		t.Error("SearchNodes changed the puzzle")
+	}
+
+	if _, err := loadString(t, contradiction).SearchNodes(); err == nil {
+		t.Error("Contradiction searched without an error")
+	}
+}
+
+func TestNakedSingleCascade(t *testing.T) {
+	// With the first row and column blank, every cell but the corner is a
+	// naked single, and the corner becomes one once the rest are placed
+	grid := gridOf(sampleSolutions["medium.txt"])
+	for i := 0; i < Size; i++ {
+		grid[0][i], grid[i][0] = 0, 0
+	}
+	s, err := NewSudoku(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.NakedSingleCascade(), 2*Size-1; got != want {
+		t.Errorf("Cascade placed %d, want %d", got, want)
+	}
+	if got := len(s.Cells().UnsetOnly()); got != 2*Size-1 {
+		t.Errorf("The cascade changed the puzzle, leaving %d blanks", got)
+	}
+
+	if got := blankSudoku(t).NakedSingleCascade(); got != 0 {
+		t.Errorf("Cascade placed %d on a blank board", got)
+	}
+}
+
+func TestTechniqueStats(t *testing.T) {
+	s := loadPuzzle(t, "hard1.txt")
+	blanks := len(s.Cells().UnsetOnly())
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := s.TechniqueStats()
+	if len(stats) < 3 {
+		t.Errorf("Only %d techniques used: %v", len(stats), stats)
+	}
+	if got := stats["naked single"] + stats["hidden single"]; got != blanks {
+		t.Errorf("Singles placed %d values, want %d", got, blanks)
+	}
+
+	eliminated := 0
+	for _, step := range s.Steps() {
+		if step.Action == Eliminate {
+			eliminated += len(step.Cells)
+		}
+	}
+	total := 0
+	for _, count := range stats {
+		total += count
+	}
+	if total != blanks+eliminated {
+		t.Errorf("Tallied %d changes, want %d placements and %d eliminations", total, blanks, eliminated)
+	}
+	if stats["pointing pair"]+stats["claiming"] == 0 {
+		t.Errorf("No eliminations by intersections: %v", stats)
+	}
+}
@@ -1,10 +1,13 @@
 package internal
 
-import "fmt"
+import (
+	"fmt"
+	"math/bits"
+)
 
 const (
 	empty Moves = 0
-	full  Moves = 0b111111111
+	full  Moves = 1<<Size - 1
 )
 
 type Moves int
@@ -33,9 +36,17 @@ func (m *Moves) Remove(value int) bool {
 	return true
 }
 
+func (m *Moves) Equal(other Moves) bool {
+	return *m == other
+}
+
+func (m *Moves) Count() int {
+	return bits.OnesCount(uint(*m))
+}
+
 func (m *Moves) Slice() []int {
 	moves := make([]int, 0)
-	for value := 1; value <= 9; value++ {
+	for value := 1; value <= Size; value++ {
 		if m.Contains(value) {
 			moves = append(moves, value)
 		}
@@ -44,7 +55,7 @@ func (m *Moves) Slice() []int {
 }
 
 func mask(value int) Moves {
-	if value < 1 || value > 9 {
+	if value < 1 || value > Size {
 		panic(fmt.Errorf("value out of range: %d", value))
 	}
 	return Moves(1 << (value - 1))
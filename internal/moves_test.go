@@ -0,0 +1,17 @@
+package internal
+
+import "testing"
+
+func TestMovesEqual(t *testing.T) {
+	a := movesOf(1, 5, 9)
+	if !a.Equal(movesOf(9, 5, 1)) {
+		t.Error("Same values in another order are not equal")
+	}
+	if a.Equal(movesOf(1, 5)) || a.Equal(movesOf(1, 5, 8)) {
+		t.Error("Different values are equal")
+	}
+	none := empty
+	if !none.Equal(Moves(0)) || none.Equal(full) {
+		t.Error("Empty mask compares wrongly")
+	}
+}
@@ -0,0 +1,507 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+type ParseOption func(*parser)
+
+// Labeled parses grids printed with a header line of column numbers 1-9 and
+// a row label (A-I) at the start of each row. Cell values are read from the
+// character positions of the column numbers in the header.
+func Labeled() ParseOption {
+	return func(p *parser) {
+		p.labeled = true
+	}
+}
+
+// SingleLine parses puzzles written as a line of 81 characters, with the
+// digits 1-9 for clues and '.' or '0' for blank cells. The rows may also be
+// separated by '/' or ',', as in query strings.
+func SingleLine() ParseOption {
+	return func(p *parser) {
+		p.singleLine = true
+	}
+}
+
+// MarkedGivens treats only digits enclosed in brackets, such as [5], as
+// givens. Other filled digits are loaded as player entries, which can be
+// edited later. Without this option every filled digit is a given.
+func MarkedGivens() ParseOption {
+	return func(p *parser) {
+		p.markedGivens = true
+	}
+}
+
+// BlankGlyph parses grids in which every blank cell is written as glyph,
+// such as '_' or '*'. Each row must then contain exactly nine cells, and any
+// character other than digits, the glyph, whitespace and the '|', '-' and '+'
+// separators is an error.
+func BlankGlyph(glyph rune) ParseOption {
+	return func(p *parser) {
+		p.blankGlyph = glyph
+	}
+}
+
+// Output sets the writer of the loaded puzzles, so that the board printed
+// while loading goes there too. See SetOutput.
+func Output(w io.Writer) ParseOption {
+	return func(p *parser) {
+		p.out = w
+	}
+}
+
+type parser struct {
+	labeled      bool
+	singleLine   bool
+	markedGivens bool
+	blankGlyph   rune
+	out          io.Writer
+
+	// Number of input lines read so far
+	line int
+}
+
+type Format int
+
+const (
+	GridFormat Format = iota
+	SingleLineFormat
+	LabeledFormat
+	SDKFormat
+	DelimitedFormat
+)
+
+func (f Format) String() string {
+	switch f {
+	case GridFormat:
+		return "grid"
+	case SingleLineFormat:
+		return "single-line"
+	case LabeledFormat:
+		return "labeled grid"
+	case SDKFormat:
+		return "sdk"
+	case DelimitedFormat:
+		return "delimited rows"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+type parsedPuzzle struct {
+	values [Size][Size]int
+	format Format
+
+	// Filled cells that were not marked as givens
+	entries [Size][Size]bool
+
+	// Input line each row was read from
+	lines [Size]int
+}
+
+func (pz *parsedPuzzle) set(row, col int, c byte, marked bool, p *parser) {
+	pz.values[row][col] = int(c) - '0'
+	pz.entries[row][col] = p.markedGivens && !marked
+	pz.lines[row] = p.line
+}
+
+func newSudokuFromPuzzle(pz parsedPuzzle, p *parser) (*Sudoku, error) {
+	s, err := NewSudoku(pz.values)
+
+	var given *givenError
+	if errors.As(err, &given) {
+		err = fmt.Errorf("%v (input line %d)", err, pz.lines[given.row])
+	}
+	if s != nil {
+		s.format = pz.format
+		if p.out != nil {
+			s.SetOutput(p.out)
+		}
+		for row := 0; row < Size; row++ {
+			for col := 0; col < Size; col++ {
+				if pz.entries[row][col] {
+					s.board[row][col].given = false
+				}
+			}
+		}
+	}
+	return s, err
+}
+
+func newParser(options []ParseOption) *parser {
+	p := &parser{}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// newScanner splits input into lines ending in "\n", "\r\n" or a lone "\r",
+// so puzzles saved on any system parse the same way.
+func newScanner(reader io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(scanLines)
+	return scanner
+}
+
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		// A "\r" at the end of the buffer may be followed by a "\n"
+		if !atEOF {
+			return 0, nil, nil
+		}
+		return i + 1, data[:i], nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func (p *parser) scan(scanner *bufio.Scanner) bool {
+	if !scanner.Scan() {
+		return false
+	}
+	p.line++
+	return true
+}
+
+func (p *parser) parse(reader io.Reader) (parsedPuzzle, error) {
+	scanner := newScanner(reader)
+	b, _, err := p.next(scanner)
+	if err != nil {
+		return b, err
+	}
+	return b, scanner.Err()
+}
+
+func (p *parser) parseAll(reader io.Reader) ([]parsedPuzzle, error) {
+	scanner := newScanner(reader)
+	boards := make([]parsedPuzzle, 0)
+	for {
+		b, rows, err := p.next(scanner)
+		if err != nil {
+			return nil, err
+		}
+		if rows == 0 {
+			break
+		}
+		boards = append(boards, b)
+	}
+	return boards, scanner.Err()
+}
+
+// next parses the next puzzle from the scanner, returning the number of rows
+// read so callers can tell when the input is exhausted.
+func (p *parser) next(scanner *bufio.Scanner) (parsedPuzzle, int, error) {
+	switch {
+	case p.singleLine:
+		return p.parseSingleLine(scanner)
+	case p.labeled:
+		return p.parseLabeled(scanner)
+	case p.blankGlyph != 0:
+		return p.parseGlyphs(scanner)
+	default:
+		return p.parsePlain(scanner)
+	}
+}
+
+func (p *parser) parsePlain(scanner *bufio.Scanner) (parsedPuzzle, int, error) {
+	var b = parsedPuzzle{}
+	row := 0
+
+	for row < Size && p.scan(scanner) {
+		// Without this check, the '.' and '0' blanks of a single-line puzzle
+		// would be stripped and its clues packed into the first rows.
+		if row == 0 {
+			text, marked := p.cellText(scanner.Text(), isNotSpace)
+			if isSingleLine(text) {
+				b, err := p.singleLinePuzzle(text, marked)
+				return b, Size, err
+			}
+			if isDelimited(text) {
+				b, err := p.delimitedPuzzle(text, marked)
+				return b, Size, err
+			}
+		}
+
+		line, marked := p.cellText(scanner.Text(), isPlainCell)
+		if len(line) > 0 {
+			for col := 0; col < Size && col < len(line); col++ {
+				if line[col] != ' ' {
+					b.set(row, col, line[col], marked[col], p)
+				}
+			}
+			row++
+		}
+	}
+
+	return b, row, nil
+}
+
+func (p *parser) parseGlyphs(scanner *bufio.Scanner) (parsedPuzzle, int, error) {
+	var b = parsedPuzzle{}
+	row := 0
+
+	for row < Size && p.scan(scanner) {
+		line := scanner.Text()
+		col := 0
+		marked := false
+
+		for _, c := range line {
+			switch {
+			case c >= '1' && c <= '9':
+				if col < Size {
+					b.set(row, col, byte(c), marked, p)
+				}
+				col++
+			case c == p.blankGlyph:
+				col++
+			case p.markedGivens && c == '[':
+				marked = true
+			case p.markedGivens && c == ']':
+				marked = false
+			case c == '|' || c == '-' || c == '+' || unicode.IsSpace(c):
+			default:
+				return b, row, fmt.Errorf("Unexpected character %q in %q", c, line)
+			}
+		}
+
+		if col == 0 {
+			continue
+		}
+		if col != Size {
+			return b, row, fmt.Errorf("Expected 9 cells but found %d in %q", col, line)
+		}
+		row++
+	}
+
+	return b, row, nil
+}
+
+func (p *parser) parseSingleLine(scanner *bufio.Scanner) (parsedPuzzle, int, error) {
+	for p.scan(scanner) {
+		line, marked := p.cellText(scanner.Text(), isNotSpace)
+		if line == "" {
+			continue
+		}
+		if isDelimited(line) {
+			b, err := p.delimitedPuzzle(line, marked)
+			return b, Size, err
+		}
+		b, err := p.singleLinePuzzle(line, marked)
+		return b, Size, err
+	}
+
+	return parsedPuzzle{}, 0, nil
+}
+
+func (p *parser) singleLinePuzzle(line string, marked []bool) (parsedPuzzle, error) {
+	var b = parsedPuzzle{format: SingleLineFormat}
+
+	if len(line) != cellCount {
+		return b, fmt.Errorf("Expected 81 cells but found %d: %q", len(line), line)
+	}
+
+	for i := 0; i < cellCount; i++ {
+		c := line[i]
+		switch {
+		case c >= '1' && c <= '9':
+			b.set(i/Size, i%Size, c, marked[i], p)
+		case c == '.' || c == '0':
+		default:
+			return b, fmt.Errorf("Unexpected character %q in %q", c, line)
+		}
+	}
+
+	return b, nil
+}
+
+func isSingleLine(line string) bool {
+	if len(line) != cellCount {
+		return false
+	}
+	for i := 0; i < len(line); i++ {
+		if (line[i] < '0' || line[i] > '9') && line[i] != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDelimited(line string) bool {
+	return strings.ContainsAny(line, "/,")
+}
+
+// delimitedPuzzle parses a single line holding nine rows of nine cells each,
+// separated by '/' or ','.
+func (p *parser) delimitedPuzzle(line string, marked []bool) (parsedPuzzle, error) {
+	var b = parsedPuzzle{format: DelimitedFormat}
+	row, col := 0, 0
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '/' || c == ',':
+			if col != Size {
+				return b, fmt.Errorf("Expected 9 cells but found %d in row %d of %q", col, row+1, line)
+			}
+			row++
+			col = 0
+		case c >= '1' && c <= '9':
+			if row < Size && col < Size {
+				b.set(row, col, c, marked[i], p)
+			}
+			col++
+		case c == '.' || c == '0':
+			col++
+		default:
+			return b, fmt.Errorf("Unexpected character %q in %q", c, line)
+		}
+	}
+
+	if col > 0 {
+		if col != Size {
+			return b, fmt.Errorf("Expected 9 cells but found %d in row %d of %q", col, row+1, line)
+		}
+		row++
+	}
+	if row != Size {
+		return b, fmt.Errorf("Expected 9 rows but found %d in %q", row, line)
+	}
+
+	return b, nil
+}
+
+func (p *parser) parseLabeled(scanner *bufio.Scanner) (parsedPuzzle, int, error) {
+	var b = parsedPuzzle{format: LabeledFormat}
+	var columns []int
+	row := 0
+
+	for row < Size && p.scan(scanner) {
+		line := scanner.Text()
+
+		if columns == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			header, err := headerColumns(line)
+			if err != nil {
+				return b, 0, err
+			}
+			columns = header
+			continue
+		}
+
+		label := strings.TrimSpace(line)
+		if label == "" || !isRowLabel(label[0]) {
+			continue
+		}
+
+		for col, index := range columns {
+			if index < len(line) && line[index] >= '1' && line[index] <= '9' {
+				b.set(row, col, line[index], index > 0 && line[index-1] == '[', p)
+			}
+		}
+		row++
+	}
+
+	return b, row, nil
+}
+
+// cellText returns the characters of line accepted by keep. When givens are
+// marked, brackets are dropped and each character reports whether it was
+// enclosed in them.
+func (p *parser) cellText(line string, keep func(byte) bool) (string, []bool) {
+	text := make([]byte, 0, len(line))
+	marked := make([]bool, 0, len(line))
+	inside := false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case p.markedGivens && c == '[':
+			inside = true
+		case p.markedGivens && c == ']':
+			inside = false
+		case keep(c):
+			text = append(text, c)
+			marked = append(marked, inside)
+		}
+	}
+
+	return string(text), marked
+}
+
+func isPlainCell(c byte) bool {
+	return c == ' ' || (c >= '1' && c <= '9')
+}
+
+func isNotSpace(c byte) bool {
+	return c != ' ' && c != '\t' && c != '\r' && c != '\n' && c != '\v' && c != '\f'
+}
+
+func headerColumns(line string) ([]int, error) {
+	columns := make([]int, 0, Size)
+	for i := 0; i < len(line); i++ {
+		if line[i] >= '1' && line[i] <= '9' {
+			if int(line[i]-'0') != len(columns)+1 {
+				return nil, fmt.Errorf("Invalid column header: %q", line)
+			}
+			columns = append(columns, i)
+		}
+	}
+
+	if len(columns) != Size {
+		return nil, fmt.Errorf("Invalid column header: %q", line)
+	}
+
+	return columns, nil
+}
+
+func isRowLabel(c byte) bool {
+	return (c >= 'A' && c <= 'I') || (c >= 'a' && c <= 'i')
+}
+
+// Inspect parses a puzzle without validating it, returning the format it was
+// read as and the cells as nine rows of nine characters with '.' for blanks.
+// Comparing this with the original input shows how it was interpreted.
+func Inspect(reader io.Reader, options ...ParseOption) (Format, string, error) {
+	b, err := newParser(options).parse(reader)
+	if err != nil {
+		return b.format, "", err
+	}
+
+	var text strings.Builder
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
+			if value := b.values[row][col]; value != 0 {
+				text.WriteByte(byte('0' + value))
+			} else {
+				text.WriteByte('.')
+			}
+		}
+		text.WriteByte('\n')
+	}
+	return b.format, text.String(), nil
+}
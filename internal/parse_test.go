@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const labeledMedium = `
+    1 2 3   4 5 6   7 8 9
+A   3 . 6 | 5 . 8 | 4 . .
+B   5 2 . | . . . | . . .
+C   . 8 7 | . . . | . 3 1
+    ------+-------+------
+D   . . 3 | . 1 . | . 8 .
+E   9 . . | 8 6 3 | . . 5
+F   . 5 . | . 9 . | 6 . .
+    ------+-------+------
+G   1 3 . | . . . | 2 5 .
+H   . . . | . . . | . 7 4
+I   . . 5 | 2 . 6 | 3 . .
+`
+
+func TestParseLabeled(t *testing.T) {
+	s := loadString(t, labeledMedium, Labeled())
+	if s.Format() != LabeledFormat {
+		t.Errorf("Parsed as %v, want %v", s.Format(), LabeledFormat)
+	}
+	if want := loadPuzzle(t, "medium.txt").Grid(); s.Grid() != want {
+		t.Errorf("Got\n%v\nwant\n%v", s, want)
+	}
+}
+
+func TestParseLabeledInvalidHeader(t *testing.T) {
+	text := strings.Replace(labeledMedium, "7 8 9", "7 9 8", 1)
+	if _, err := NewSudokuFromString(text, Labeled()); err == nil {
+		t.Error("Header with columns out of order accepted")
+	}
+}
+
+func TestMarkedGivens(t *testing.T) {
+	// The first row of medium.txt with only the 3 and the 6 marked
+	s := loadString(t, "[3].[6]5.84.."+strings.Repeat(".", cellCount-Size), SingleLine(), MarkedGivens())
+
+	if want := [Size]int{3, 0, 6, 5, 0, 8, 4, 0, 0}; s.Grid()[0] != want {
+		t.Errorf("Got row %v, want %v", s.Grid()[0], want)
+	}
+	for col, want := range []bool{true, false, true, false, false, false, false} {
+		if got := s.Cell(0, col).Given(); got != want {
+			t.Errorf("Column %d given: %v, want %v", col+1, got, want)
+		}
+	}
+}
+
+func TestUnmarkedDigitsAreGivens(t *testing.T) {
+	s := loadString(t, singleLine("3.65.84.."), SingleLine())
+	for _, cell := range s.Row(0) {
+		if got, want := cell.Given(), cell.value != 0; got != want {
+			t.Errorf("Column %d given: %v, want %v", cell.col+1, got, want)
+		}
+	}
+}
+
+func TestMarkedGivensInGrid(t *testing.T) {
+	text := strings.Replace(plainMedium(t), "3 65 84", "[3] 65 84", 1)
+	s := loadString(t, text, MarkedGivens())
+	if !s.Cell(0, 0).Given() || s.Cell(0, 2).Given() {
+		t.Error("Marked given not told apart from the entries in a grid")
+	}
+	if s.Grid() != loadPuzzle(t, "medium.txt").Grid() {
+		t.Errorf("Got\n%v", s)
+	}
+}
+
+// plainMedium returns the text of medium.txt, a plain grid.
+func plainMedium(t *testing.T) string {
+	t.Helper()
+	text, err := os.ReadFile(filepath.Join("..", "puzzles", "medium.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(text)
+}
+
+const underscoreMedium = `
+3_6|5_8|4__
+52_|___|___
+_87|___|_31
+---+---+---
+__3|_1_|_8_
+9__|863|__5
+_5_|_9_|6__
+---+---+---
+13_|___|25_
+___|___|_74
+__5|2_6|3__
+`
+
+func TestBlankGlyph(t *testing.T) {
+	s := loadString(t, underscoreMedium, BlankGlyph('_'))
+	if want := loadPuzzle(t, "medium.txt").Grid(); s.Grid() != want {
+		t.Errorf("Got\n%v\nwant\n%v", s, want)
+	}
+}
+
+func TestBlankGlyphRejectsOtherCharacters(t *testing.T) {
+	for name, text := range map[string]string{
+		"unexpected character": strings.Replace(underscoreMedium, "_87", "x87", 1),
+		"short row":            strings.Replace(underscoreMedium, "52_|", "52|", 1),
+		"other blank":          strings.Replace(underscoreMedium, "_87", ".87", 1),
+	} {
+		if _, err := NewSudokuFromString(text, BlankGlyph('_')); err == nil {
+			t.Errorf("Accepted a grid with a %s", name)
+		}
+	}
+}
+
+const inspectedMedium = `3.65.84..
+52.......
+.87....31
+..3.1..8.
+9..863..5
+.5..9.6..
+13....25.
+.......74
+..52.63..
+`
+
+func TestInspect(t *testing.T) {
+	line := strings.ReplaceAll(inspectedMedium, "\n", "")
+	for _, test := range []struct {
+		name   string
+		input  string
+		format Format
+	}{
+		{"grid", plainMedium(t), GridFormat},
+		{"single-line with dots", line, SingleLineFormat},
+		{"single-line with zeros", strings.ReplaceAll(line, ".", "0"), SingleLineFormat},
+	} {
+		format, text, err := Inspect(strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if format != test.format {
+			t.Errorf("%s: parsed as %v, want %v", test.name, format, test.format)
+		}
+		if text != inspectedMedium {
+			t.Errorf("%s: got\n%s", test.name, text)
+		}
+	}
+}
+
+func TestFormatOfLoadedPuzzle(t *testing.T) {
+	if got := loadPuzzle(t, "medium.txt").Format(); got != GridFormat {
+		t.Errorf("Grid parsed as %v", got)
+	}
+	if got := loadString(t, singleLine("3.65.84")).Format(); got != SingleLineFormat {
+		t.Errorf("Single line parsed as %v", got)
+	}
+}
+
+func TestConflictingGivensReportInputLine(t *testing.T) {
+	// Row B follows a blank line, the header and row A
+	text := strings.Replace(labeledMedium, "B   5 2", "B   5 5", 1)
+	_, err := NewSudokuFromString(text, Labeled(), Output(io.Discard))
+	if err == nil || !strings.Contains(err.Error(), "Row 2") || !strings.Contains(err.Error(), "(input line 4)") {
+		t.Errorf("Got error %v, want one for row 2 on input line 4", err)
+	}
+}
+
+func TestDelimitedRows(t *testing.T) {
+	slashes := strings.TrimSuffix(strings.ReplaceAll(inspectedMedium, "\n", "/"), "/")
+	want := loadPuzzle(t, "medium.txt").Grid()
+
+	for _, text := range []string{slashes, strings.ReplaceAll(slashes, "/", ",")} {
+		s := loadString(t, text)
+		if s.Grid() != want || s.Format() != DelimitedFormat {
+			t.Errorf("%s parsed as %v:\n%v", text, s.Format(), s)
+		}
+	}
+}
+
+func TestDelimitedRowsRejectsWrongShape(t *testing.T) {
+	rows := strings.Split(strings.TrimSuffix(inspectedMedium, "\n"), "\n")
+	for name, text := range map[string]string{
+		"eight rows":      strings.Join(rows[:8], "/"),
+		"ten rows":        strings.Join(append(rows, rows[0]), "/"),
+		"short row":       strings.Join(append([]string{rows[0][:8]}, rows[1:]...), "/"),
+		"long last row":   strings.Join(rows, "/") + ".",
+		"unexpected text": strings.Join(rows, "/") + "/x",
+	} {
+		if _, err := NewSudokuFromString(text, Output(io.Discard)); err == nil {
+			t.Errorf("Accepted %s: %s", name, text)
+		}
+	}
+}
+
+func TestLineEndings(t *testing.T) {
+	want := loadPuzzle(t, "medium.txt").Grid()
+	text := plainMedium(t)
+	for name, text := range map[string]string{
+		"CRLF":     strings.ReplaceAll(text, "\n", "\r\n"),
+		"lone CR":  strings.ReplaceAll(text, "\n", "\r"),
+		"mixed":    strings.Replace(strings.Replace(text, "\n", "\r\n", 3), "\n", "\r", 2),
+		"blank CR": "\r\n" + strings.ReplaceAll(text, "\n", "\r\n"),
+	} {
+		if s := loadString(t, text); s.Grid() != want {
+			t.Errorf("%s: got\n%v", name, s)
+		}
+	}
+
+	first, second := loadPuzzle(t, "medium.txt"), loadPuzzle(t, "hard1.txt")
+	puzzles, err := StringSource{Text: first.String() + "\r" + second.String() + "\r", Options: []ParseOption{SingleLine()}}.Puzzles()
+	if err != nil || len(puzzles) != 2 || puzzles[1].Grid() != second.Grid() {
+		t.Errorf("Single lines ending in lone CR: got %v, %v", puzzles, err)
+	}
+}
+
+func TestLettersRejectedOnNineByNine(t *testing.T) {
+	// Letters only stand for values on boards larger than 9x9
+	for _, letter := range []string{"A", "G", "a"} {
+		text := strings.Replace(inspectedMedium, "3.65", "3"+letter+"65", 1)
+		if _, err := NewSudokuFromString(strings.ReplaceAll(text, "\n", ""), SingleLine(), Output(io.Discard)); err == nil {
+			t.Errorf("Accepted the letter %s", letter)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package internal
+
+import "fmt"
+
+// ScanOrder controls the order in which techniques visit cells and groups.
+// It never changes whether a puzzle can be solved, only which moves are found
+// first and so how the steps read.
+type ScanOrder int
+
+const (
+	// RowMajor visits cells row by row, and groups as rows, then columns,
+	// then squares.
+	RowMajor ScanOrder = iota
+
+	// BoxFirst visits cells square by square, and groups as squares, then
+	// rows, then columns, so progress reads box by box.
+	BoxFirst
+)
+
+func (o ScanOrder) String() string {
+	switch o {
+	case RowMajor:
+		return "row-major"
+	case BoxFirst:
+		return "box-first"
+	}
+	return fmt.Sprintf("ScanOrder(%d)", int(o))
+}
+
+// SetScanOrder changes the order in which techniques visit the board. The
+// default is RowMajor.
+func (s *Sudoku) SetScanOrder(order ScanOrder) {
+	s.scanOrder = order
+}
+
+func (s *Sudoku) scanCells() Cells {
+	if s.scanOrder != BoxFirst {
+		return s.Cells()
+	}
+	cells := make(Cells, 0, cellCount)
+	for _, square := range s.Squares() {
+		cells = append(cells, square...)
+	}
+	return cells
+}
+
+func (s *Sudoku) scanGroups() []Cells {
+	if s.scanOrder != BoxFirst {
+		return s.Groups()
+	}
+	return append(append(s.Squares(), s.Rows()...), s.Cols()...)
+}
+
+// A group is identified by the positions of its first and last cells, which
+// differ for every row, column and square.
+type scanKey struct {
+	technique   string
+	first, last int
+}
+
+// The values and candidates of a group's cells, as last scanned
+type groupState [Size]Moves
+
+// unchanged reports whether a technique already scanned the group in its
+// current state, and otherwise records the state. Hidden singles and subsets
+// depend only on the cells of the group they examine, so scanning a group
+// again finds nothing new until one of its cells changes. Each cell is
+// compared directly, so changes made outside the solver are noticed too.
+func (s *Sudoku) unchanged(technique string, group Cells) bool {
+	first, last := group[0], group[len(group)-1]
+	key := scanKey{technique, first.row*Size + first.col, last.row*Size + last.col}
+
+	var state groupState
+	for i, cell := range group {
+		state[i] = cell.moves | Moves(cell.value)<<Size
+	}
+
+	if s.scanned == nil {
+		s.scanned = make(map[scanKey]groupState)
+	}
+	if previous, ok := s.scanned[key]; ok && previous == state {
+		return true
+	}
+	s.scanned[key] = state
+	return false
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestBoxFirstScanOrder(t *testing.T) {
+	rowMajor := loadPuzzle(t, "hard1.txt")
+	boxFirst := loadPuzzle(t, "hard1.txt")
+	boxFirst.SetScanOrder(BoxFirst)
+
+	for _, s := range []*Sudoku{rowMajor, boxFirst} {
+		if err := s.Solve(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if rowMajor.Grid() != boxFirst.Grid() {
+		t.Errorf("Scan orders found different solutions:\n%v\n%v", rowMajor, boxFirst)
+	}
+	if reflect.DeepEqual(stepNames(rowMajor), stepNames(boxFirst)) {
+		t.Error("Box-first order took the same steps")
+	}
+}
+
+func TestScanOrderCoversBoard(t *testing.T) {
+	s := blankSudoku(t)
+	s.SetScanOrder(BoxFirst)
+
+	cells := s.scanCells()
+	if len(cells) != cellCount || cells[BoxSize] != s.Cell(1, 0) {
+		t.Errorf("Box-first cells start %s", cells[:Size].LocationString())
+	}
+	if groups := s.scanGroups(); len(groups) != 3*Size || !reflect.DeepEqual(groups[0], s.Square(0, 0)) {
+		t.Errorf("Box-first groups do not start with the squares")
+	}
+}
+
+// stepNames describes each step of a solve.
+func stepNames(s *Sudoku) []string {
+	names := make([]string, 0, len(s.Steps()))
+	for _, step := range s.Steps() {
+		names = append(names, step.Describe())
+	}
+	return names
+}
+
+// solveNaively solves with the record of scanned groups dropped after every
+// step, so that no group is skipped once the board has changed.
+func solveNaively(s *Sudoku) error {
+	s.onStep = func(Step) {
+		s.scanned = nil
+	}
+	defer func() { s.onStep = nil }()
+	return s.Solve()
+}
+
+func TestSkippingUnchangedGroupsKeepsSteps(t *testing.T) {
+	var puzzles []*Sudoku
+	for _, name := range []string{"medium.txt", "hard1.txt", "hard2.txt", "expert1.txt", "expert2.txt", "expert3.txt"} {
+		s := loadPuzzle(t, name)
+		for rotation := 0; rotation < 4; rotation++ {
+			puzzles = append(puzzles, s, s.Transpose())
+			s = s.RotateCW()
+		}
+	}
+
+	for i, puzzle := range puzzles {
+		optimized, naive := puzzle.quietClone(), puzzle.quietClone()
+		optimizedErr, naiveErr := optimized.Solve(), solveNaively(naive)
+
+		if optimized.Grid() != naive.Grid() || (optimizedErr == nil) != (naiveErr == nil) {
+			t.Errorf("Puzzle %d: got %v\n%v\nwant %v\n%v", i+1, optimizedErr, optimized, naiveErr, naive)
+			continue
+		}
+		if got, want := stepNames(optimized), stepNames(naive); !reflect.DeepEqual(got, want) {
+			t.Errorf("Puzzle %d took %d steps, want the %d of the naive solve", i+1, len(got), len(want))
+		}
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	var puzzles []*Sudoku
+	for _, name := range []string{"medium.txt", "hard1.txt", "hard2.txt", "expert1.txt", "expert2.txt"} {
+		s, err := NewSudokuFromFile("../puzzles/"+name, Output(io.Discard))
+		if err != nil {
+			b.Fatal(err)
+		}
+		puzzles = append(puzzles, s)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, puzzle := range puzzles {
+			if err := puzzle.Clone().Solve(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
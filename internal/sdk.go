@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"io"
+	"strings"
+)
+
+// Metadata holds the descriptive lines that puzzle collections store next to
+// a puzzle.
+type Metadata struct {
+	Author      string
+	Description string
+	Difficulty  string
+	Source      string
+
+	// Comment lines, and metadata lines with an unrecognized tag
+	Comments []string
+}
+
+func (s *Sudoku) Metadata() Metadata {
+	return s.metadata
+}
+
+// NewSudokuFromSDK reads a puzzle in the SadMan Software .sdk format: nine
+// rows of nine cells with '.' for blanks, preceded by optional lines starting
+// with '#'. A tag letter after the '#' names the kind of metadata: A for
+// author, D for description, L for the difficulty level, S for the source and
+// C for a comment. Options apply as in NewSudokuFromReader.
+func NewSudokuFromSDK(r io.Reader, options ...ParseOption) (*Sudoku, error) {
+	var metadata Metadata
+	var grid strings.Builder
+
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			metadata.add(line[1:])
+			line = ""
+		}
+		// Comments are blanked rather than dropped to keep line numbers in
+		// parse errors accurate.
+		grid.WriteString(line)
+		grid.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	p := newParser(append([]ParseOption{BlankGlyph('.')}, options...))
+	b, err := p.parse(strings.NewReader(grid.String()))
+	if err != nil {
+		return nil, err
+	}
+	b.format = SDKFormat
+
+	s, err := newSudokuFromPuzzle(b, p)
+	s.metadata = metadata
+	s.printInitialized()
+
+	return s, err
+}
+
+func (m *Metadata) add(line string) {
+	if line == "" {
+		return
+	}
+	value := strings.TrimSpace(line[1:])
+	switch line[0] {
+	case 'A':
+		m.Author = value
+	case 'D':
+		m.Description = value
+	case 'L':
+		m.Difficulty = value
+	case 'S':
+		m.Source = value
+	case 'C':
+		m.Comments = append(m.Comments, value)
+	default:
+		m.Comments = append(m.Comments, strings.TrimSpace(line))
+	}
+}
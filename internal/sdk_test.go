@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleSDK = `#AJohn Doe
+#DA medium puzzle
+#LMedium
+#Cfrom the sample collection
+#Xunknown tag
+3.65.84..
+52.......
+.87....31
+..3.1..8.
+9..863..5
+.5..9.6..
+13....25.
+.......74
+..52.63..
+`
+
+func TestNewSudokuFromSDK(t *testing.T) {
+	s, err := NewSudokuFromSDK(strings.NewReader(sampleSDK), Output(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := loadPuzzle(t, "medium.txt").Grid(); s.Grid() != want {
+		t.Errorf("Got\n%v", s)
+	}
+	if s.Format() != SDKFormat {
+		t.Errorf("Parsed as %v", s.Format())
+	}
+
+	want := Metadata{
+		Author:      "John Doe",
+		Description: "A medium puzzle",
+		Difficulty:  "Medium",
+		Comments:    []string{"from the sample collection", "Xunknown tag"},
+	}
+	if got := s.Metadata(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Got metadata %+v, want %+v", got, want)
+	}
+}
+
+func TestNewSudokuFromSDKReportsInputLine(t *testing.T) {
+	// Row 2 is on line 7, after the five comment lines and row 1
+	text := strings.Replace(sampleSDK, "52......", "55......", 1)
+	if _, err := NewSudokuFromSDK(strings.NewReader(text), Output(io.Discard)); err == nil || !strings.Contains(err.Error(), "(input line 7)") {
+		t.Errorf("Got error %v, want one on input line 7", err)
+	}
+}
+
+func TestLoadStringResetsMetadata(t *testing.T) {
+	s, err := NewSudokuFromSDK(strings.NewReader(sampleSDK), Output(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LoadString(loadPuzzle(t, "hard1.txt").String()); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Metadata(); !reflect.DeepEqual(got, Metadata{}) {
+		t.Errorf("Metadata kept after loading another puzzle: %+v", got)
+	}
+}
@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// SetBranchStrategy replaces the heuristic that picks which unset cell the
+// search branches on next. It is passed the unset cells, and must return one
+// of them. The default picks the cell with the fewest candidates.
+func (s *Sudoku) SetBranchStrategy(strategy func(Cells) *Cell) {
+	s.branchStrategy = strategy
+}
+
+func (s *Sudoku) branchCell() *Cell {
+	unset := s.Cells().UnsetOnly()
+	if len(unset) == 0 {
+		return nil
+	}
+	if s.branchStrategy != nil {
+		return s.branchStrategy(unset)
+	}
+	return fewestCandidates(unset)
+}
+
+func fewestCandidates(cells Cells) *Cell {
+	// A cell without candidates fails at once, so it is the best choice
+	for _, cell := range cells {
+		if cell.moves == empty {
+			return cell
+		}
+	}
+	return cells.MinCandidateCell()
+}
+
+func (s *Sudoku) CountSolutions(limit int) int {
+	branch := s.branchCell()
+	if branch == nil {
+		return 1
+	}
+
+	count := 0
+	for _, value := range branch.Moves() {
+		clone := s.Clone()
+		if err := clone.PlayMove(branch.row, branch.col, value); err != nil {
+			continue
+		}
+		count += clone.CountSolutions(limit - count)
+		if count >= limit {
+			break
+		}
+	}
+	return count
+}
+
+// Solutions finds up to limit completed grids by backtracking. Each solution
+// is a separate board, independent of s and of each other.
+func (s *Sudoku) Solutions(limit int) ([]*Sudoku, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("Solution limit must be positive: %d", limit)
+	}
+	return s.collectSolutions(limit, make([]*Sudoku, 0, limit)), nil
+}
+
+func (s *Sudoku) collectSolutions(limit int, solutions []*Sudoku) []*Sudoku {
+	branch := s.branchCell()
+	if branch == nil {
+		return append(solutions, s.Clone())
+	}
+
+	for _, value := range branch.Moves() {
+		clone := s.Clone()
+		if err := clone.PlayMove(branch.row, branch.col, value); err != nil {
+			continue
+		}
+		solutions = clone.collectSolutions(limit, solutions)
+		if len(solutions) >= limit {
+			break
+		}
+	}
+	return solutions
+}
+
+func (s *Sudoku) RemovableClues() Cells {
+	removable := make(Cells, 0)
+	if s.CountSolutions(2) != 1 {
+		return removable
+	}
+
+	// Values placed since loading would make every given look redundant
+	grid := s.givenGrid()
+	for _, cell := range s.Cells() {
+		if !cell.given {
+			continue
+		}
+
+		without := grid
+		without[cell.row][cell.col] = 0
+		candidate, err := NewSudoku(without)
+		if err != nil {
+			continue
+		}
+		if candidate.CountSolutions(2) == 1 {
+			removable = append(removable, cell)
+		}
+	}
+	return removable
+}
+
+// IsMinimal reports whether the puzzle has a unique solution and every given
+// is needed for it: removing any one of them lets in a second solution.
+func (s *Sudoku) IsMinimal() bool {
+	return s.CountSolutions(2) == 1 && len(s.RemovableClues()) == 0
+}
+
+func (s *Sudoku) fillRandomly(r *rand.Rand) bool {
+	branch := s.branchCell()
+	if branch == nil {
+		return true
+	}
+
+	values := branch.Moves()
+	r.Shuffle(len(values), func(i, j int) {
+		values[i], values[j] = values[j], values[i]
+	})
+
+	for _, value := range values {
+		clone := s.Clone()
+		if err := clone.PlayMove(branch.row, branch.col, value); err != nil {
+			continue
+		}
+		if clone.fillRandomly(r) {
+			s.board = clone.board
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package internal
+
+import "testing"
+
+func TestCountSolutions(t *testing.T) {
+	if got := loadPuzzle(t, "medium.txt").CountSolutions(2); got != 1 {
+		t.Errorf("medium.txt has %d solutions, want 1", got)
+	}
+	if got := loadPuzzle(t, "blank.txt").CountSolutions(5); got != 5 {
+		t.Errorf("Blank board counted %d solutions with a limit of 5", got)
+	}
+	if got := loadString(t, contradiction).CountSolutions(2); got != 0 {
+		t.Errorf("Contradiction has %d solutions", got)
+	}
+}
+
+func TestRemovableClues(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	removable := s.RemovableClues()
+	if len(removable) == 0 {
+		t.Fatal("No removable clues in medium.txt")
+	}
+
+	for _, cell := range removable {
+		if !cell.Given() {
+			t.Errorf("(%d,%d) is not a clue", cell.row+1, cell.col+1)
+		}
+		grid := s.Grid()
+		grid[cell.row][cell.col] = 0
+		without, err := NewSudoku(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := without.CountSolutions(2); got != 1 {
+			t.Errorf("Removing (%d,%d) leaves %d solutions", cell.row+1, cell.col+1, got)
+		}
+	}
+}
+
+func TestRemovableCluesIgnoresSolvedCells(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	before := s.RemovableClues()
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if after := s.RemovableClues(); len(after) != len(before) {
+		t.Errorf("%d removable clues before solving, %d after", len(before), len(after))
+	}
+}
+
+func TestRemovableCluesNeedsUniqueSolution(t *testing.T) {
+	if got := loadPuzzle(t, "blank.txt").RemovableClues(); len(got) != 0 {
+		t.Errorf("Blank board has removable clues %s", got.LocationString())
+	}
+}
+
+func TestSetBranchStrategy(t *testing.T) {
+	s := loadPuzzle(t, "expert3.txt")
+	calls := 0
+	s.SetBranchStrategy(func(cells Cells) *Cell {
+		calls++
+		return cells[0]
+	})
+
+	if got := s.CountSolutions(2); got != 1 {
+		t.Errorf("Got %d solutions, want 1", got)
+	}
+	if calls == 0 {
+		t.Error("The strategy was never consulted")
+	}
+
+	clone := s.Clone()
+	calls = 0
+	clone.CountSolutions(2)
+	if calls == 0 {
+		t.Error("Clone dropped the strategy")
+	}
+}
+
+// twoSolutions returns the solution of medium.txt with rows 1 and 7 of
+// columns 1 and 2 left blank. The 3 and the 1 there can be swapped.
+func twoSolutions(t *testing.T) *Sudoku {
+	t.Helper()
+	grid := solutionOf(t, loadPuzzle(t, "medium.txt"))
+	grid[0][0], grid[0][1], grid[6][0], grid[6][1] = 0, 0, 0, 0
+	s, err := NewSudoku(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSolutions(t *testing.T) {
+	s := twoSolutions(t)
+	solutions, err := s.Solutions(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(solutions) != 2 {
+		t.Fatalf("Got %d solutions, want 2", len(solutions))
+	}
+
+	first, second := solutions[0], solutions[1]
+	if !first.IsSolved() || !second.IsSolved() || first.Grid() == second.Grid() {
+		t.Errorf("Got solutions\n%v\n%v", first, second)
+	}
+	if first.Cell(0, 0).value+second.Cell(0, 0).value != 3+1 {
+		t.Errorf("Row 1 column 1 is %d and %d, want 3 and 1", first.Cell(0, 0).value, second.Cell(0, 0).value)
+	}
+
+	// The solutions share nothing with the puzzle or each other
+	first.Cell(6, 0).value = 0
+	if second.Cell(6, 0).value == 0 || s.Cell(0, 0).value != 0 {
+		t.Error("Solutions are not independent")
+	}
+}
+
+func TestSolutionsLimit(t *testing.T) {
+	if solutions, _ := twoSolutions(t).Solutions(1); len(solutions) != 1 {
+		t.Errorf("Got %d solutions with a limit of 1", len(solutions))
+	}
+	if _, err := twoSolutions(t).Solutions(0); err == nil {
+		t.Error("Limit of 0 accepted")
+	}
+}
+
+func TestIsMinimal(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if s.IsMinimal() {
+		t.Error("medium.txt has removable clues but is minimal")
+	}
+
+	// Take clues away one at a time while the solution stays unique
+	for removable := s.RemovableClues(); len(removable) > 0; removable = s.RemovableClues() {
+		removable[0].value, removable[0].given = 0, false
+		s.RecomputeCandidates()
+	}
+	if !s.IsMinimal() {
+		t.Errorf("No clue can be removed, but not minimal:\n%v", s)
+	}
+	if got := s.CountSolutions(2); got != 1 {
+		t.Errorf("Reduced puzzle has %d solutions", got)
+	}
+}
+
+func TestIsMinimalNeedsUniqueSolution(t *testing.T) {
+	if loadPuzzle(t, "blank.txt").IsMinimal() {
+		t.Error("Blank board is minimal")
+	}
+	if loadString(t, contradiction).IsMinimal() {
+		t.Error("Contradiction is minimal")
+	}
+}
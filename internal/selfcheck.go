@@ -0,0 +1,33 @@
+package internal
+
+import "fmt"
+
+// EnableSelfCheck makes the solver compare every deduction with a known
+// solution, and panic as soon as a technique places a wrong value or
+// eliminates a correct one. It is meant for developing techniques: a faulty
+// elimination is caught where it happens, rather than surfacing as a
+// contradiction several steps later, or not at all. Guesses made while
+// searching are not checked.
+func (s *Sudoku) EnableSelfCheck(solution [Size][Size]int) {
+	s.selfCheck = &solution
+}
+
+func (s *Sudoku) checkPlacement(technique string, cell *Cell, value int) {
+	if s.selfCheck == nil {
+		return
+	}
+	if want := s.selfCheck[cell.row][cell.col]; value != want {
+		panic(fmt.Errorf("Self-check failed: %s placed %d at row %d column %d, but the solution has %d", technique, value, cell.row+1, cell.col+1, want))
+	}
+}
+
+func (s *Sudoku) checkElimination(technique string, cells Cells, value int) {
+	if s.selfCheck == nil {
+		return
+	}
+	for _, cell := range cells {
+		if s.selfCheck[cell.row][cell.col] == value {
+			panic(fmt.Errorf("Self-check failed: %s eliminated %d from row %d column %d, which is the solution", technique, value, cell.row+1, cell.col+1))
+		}
+	}
+}
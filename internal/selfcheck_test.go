@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+// selfCheckPanic runs fn and returns the message it panicked with, if any.
+func selfCheckPanic(fn func()) (message string) {
+	defer func() {
+		if r := recover(); r != nil {
+			message = r.(error).Error()
+		}
+	}()
+	fn()
+	return ""
+}
+
+func TestSelfCheckPasses(t *testing.T) {
+	s := loadPuzzle(t, "hard2.txt")
+	s.EnableSelfCheck(solutionOf(t, s))
+	if message := selfCheckPanic(func() { s.Solve() }); message != "" {
+		t.Error(message)
+	}
+}
+
+func TestSelfCheckCatchesBadElimination(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	solution := solutionOf(t, s)
+	s.EnableSelfCheck(solution)
+
+	// A buggy technique striking the solution's 1 from row 1 column 2
+	message := selfCheckPanic(func() {
+		s.eliminateStep("buggy", Cells{s.Cell(0, 1)}, solution[0][1], "Bug")
+	})
+	if !strings.Contains(message, "buggy eliminated 1 from row 1 column 2") {
+		t.Errorf("Got panic %q", message)
+	}
+
+	message = selfCheckPanic(func() {
+		s.placeStep("buggy", s.Cell(0, 1), 9, "Bug")
+	})
+	if !strings.Contains(message, "buggy placed 9 at row 1 column 2") {
+		t.Errorf("Got panic %q", message)
+	}
+}
+
+func TestLoadStringClearsSelfCheck(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	s.EnableSelfCheck(solutionOf(t, s))
+	if err := s.LoadString(loadPuzzle(t, "hard1.txt").String()); err != nil {
+		t.Fatal(err)
+	}
+	if message := selfCheckPanic(func() { s.Solve() }); message != "" {
+		t.Errorf("The old solution was checked against the new puzzle: %s", message)
+	}
+}
@@ -0,0 +1,114 @@
+package internal
+
+import "fmt"
+
+// CheckSolution reports whether solution is a complete, valid grid that keeps
+// the givens of the puzzle, and the cells where it is not. Values placed since
+// the puzzle was loaded may be wrong, so the solution is free to differ there.
+func (s *Sudoku) CheckSolution(solution [Size][Size]int) (bool, Cells) {
+	incorrect := [Size][Size]bool{}
+
+	for _, cell := range s.Cells() {
+		value := solution[cell.row][cell.col]
+		if value < 1 || value > Size || (cell.given && cell.value != value) {
+			incorrect[cell.row][cell.col] = true
+		}
+	}
+
+	for _, group := range s.Groups() {
+		placements := [Size + 1]Cells{}
+		for _, cell := range group {
+			value := solution[cell.row][cell.col]
+			if value >= 1 && value <= Size {
+				placements[value] = append(placements[value], cell)
+			}
+		}
+
+		for _, cells := range placements {
+			if len(cells) > 1 {
+				for _, cell := range cells {
+					incorrect[cell.row][cell.col] = true
+				}
+			}
+		}
+	}
+
+	cells := make(Cells, 0)
+	for _, cell := range s.Cells() {
+		if incorrect[cell.row][cell.col] {
+			cells = append(cells, cell)
+		}
+	}
+
+	return len(cells) == 0, cells
+}
+
+func (s *Sudoku) Solved() (*Sudoku, error) {
+	solution := s.quietClone()
+	if err := solution.Solve(); err != nil {
+		return nil, err
+	}
+	solution.out, solution.stepOut = s.out, s.stepOut
+	return solution, nil
+}
+
+// IsValid reports whether the board is consistent: no group holds the same
+// value twice, and no unset cell still has a candidate placed among its peers.
+// Boards built with PlayMove are always consistent, but values set directly on
+// cells bypass these checks.
+func (s *Sudoku) IsValid() bool {
+	for _, group := range s.Groups() {
+		values := group.ValueSet()
+		if len(group.Values()) != values.Count() {
+			return false
+		}
+	}
+	for _, cell := range s.Cells().UnsetOnly() {
+		if cell.moves&s.ConstraintMask(cell.row, cell.col) != empty {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateState checks the candidates of every cell against the placed values:
+// filled cells must have no candidates left, and unset cells no candidate
+// placed among their peers. The error names the first cell that fails. Boards
+// whose cells were edited directly can be fixed with RepairState.
+func (s *Sudoku) ValidateState() error {
+	for _, cell := range s.Cells() {
+		if cell.value != 0 {
+			if cell.moves != empty {
+				return fmt.Errorf("Row %d column %d is set to %d but still has candidates %v", cell.row+1, cell.col+1, cell.value, cell.moves.Slice())
+			}
+			continue
+		}
+		stale := cell.moves & s.ConstraintMask(cell.row, cell.col)
+		if stale != empty {
+			return fmt.Errorf("Row %d column %d has candidates %v already placed in its row, column or square", cell.row+1, cell.col+1, stale.Slice())
+		}
+	}
+	return nil
+}
+
+// RepairState clears the candidates that ValidateState reports. Unlike
+// RecomputeCandidates, eliminations already made are kept.
+func (s *Sudoku) RepairState() {
+	for _, cell := range s.Cells() {
+		if cell.value != 0 {
+			cell.moves = empty
+		} else {
+			cell.moves &^= s.ConstraintMask(cell.row, cell.col)
+		}
+	}
+}
+
+// IsSolved reports whether every cell is filled and each group holds the
+// values 1-9 exactly once.
+func (s *Sudoku) IsSolved() bool {
+	return len(s.Cells().UnsetOnly()) == 0 && s.IsValid()
+}
+
+func (s *Sudoku) HasDeadCell() bool {
+	return len(s.DeadCells()) > 0
+}
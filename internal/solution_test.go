@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// solutionOf returns the solved grid of a puzzle.
+func solutionOf(t *testing.T, s *Sudoku) [Size][Size]int {
+	t.Helper()
+	solved, err := s.Solved()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return solved.Grid()
+}
+
+func TestCheckSolutionCorrect(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	ok, incorrect := s.CheckSolution(solutionOf(t, s))
+	if !ok || len(incorrect) != 0 {
+		t.Errorf("Correct solution rejected at %s", incorrect.LocationString())
+	}
+}
+
+func TestCheckSolutionConflict(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	solution := solutionOf(t, s)
+
+	// Row 1 columns 2 and 5 are blank in the puzzle. Swapping them keeps the
+	// row complete but repeats values in both columns.
+	solution[0][1], solution[0][4] = solution[0][4], solution[0][1]
+
+	ok, incorrect := s.CheckSolution(solution)
+	if ok {
+		t.Fatal("Conflicting solution accepted")
+	}
+	if !incorrect.Contains(s.Cell(0, 1)) || !incorrect.Contains(s.Cell(0, 4)) {
+		t.Errorf("Swapped cells not reported, got %s", incorrect.LocationString())
+	}
+}
+
+func TestCheckSolutionAltersClue(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	solution := solutionOf(t, s)
+	solution[0][0] = solution[0][0]%Size + 1
+
+	ok, incorrect := s.CheckSolution(solution)
+	if ok {
+		t.Fatal("Solution changing a given accepted")
+	}
+	if !incorrect.Contains(s.Cell(0, 0)) {
+		t.Errorf("Changed given not reported, got %s", incorrect.LocationString())
+	}
+}
+
+func TestCheckSolutionIgnoresPlacedValues(t *testing.T) {
+	// Row 1 column 2 is blank, and its solution is 1, not 9
+	s := loadPuzzle(t, "medium.txt")
+	solution := solutionOf(t, s)
+	if err := s.PlayMove(0, 1, 9); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, incorrect := s.CheckSolution(solution); !ok {
+		t.Errorf("Solution rejected for differing from a placed value at %s", incorrect.LocationString())
+	}
+}
+
+func TestCheckSolutionIncomplete(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	solution := solutionOf(t, s)
+	solution[4][4] = 0
+
+	if ok, _ := s.CheckSolution(solution); ok {
+		t.Error("Incomplete solution accepted")
+	}
+}
+
+func TestSolvedLeavesPuzzleUnchanged(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	grid, unset := s.Grid(), len(s.Cells().UnsetOnly())
+
+	solved, err := s.Solved()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !solved.IsSolved() {
+		t.Errorf("Solved returned an unsolved board:\n%v", solved)
+	}
+	if s.Grid() != grid || len(s.Cells().UnsetOnly()) != unset || len(s.steps) != 0 {
+		t.Error("Solved changed the puzzle")
+	}
+}
+
+func TestSolvedError(t *testing.T) {
+	if solved, err := loadPuzzle(t, "blank.txt").Solved(); solved != nil || err != ErrMultipleSolutions {
+		t.Errorf("Got %v, %v; want nil, %v", solved, err, ErrMultipleSolutions)
+	}
+}
+
+func TestSolveRejectsInconsistentBoard(t *testing.T) {
+	// A value set directly on a cell, bypassing PlayMove, repeats the 3 in row 1
+	s := loadPuzzle(t, "medium.txt")
+	s.Cell(0, 1).value = 3
+	s.Cell(0, 1).moves = empty
+
+	if s.IsValid() {
+		t.Error("Board with a repeated value is valid")
+	}
+	if result, err := s.SolveDetailed(); result != Contradiction || err != ErrInconsistent {
+		t.Errorf("Got %v, %v; want %v, %v", result, err, Contradiction, ErrInconsistent)
+	}
+	if len(s.Steps()) != 0 {
+		t.Error("Solve made progress on an inconsistent board")
+	}
+
+	// A stale candidate is inconsistent too
+	s = loadPuzzle(t, "medium.txt")
+	s.Cell(0, 1).moves.Add(3)
+	if err := s.Solve(); err != ErrInconsistent {
+		t.Errorf("Got error %v with a stale candidate, want %v", err, ErrInconsistent)
+	}
+}
+
+func TestSolveRejectsDeadCell(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	s.Cell(0, 1).moves = empty
+
+	if !s.HasDeadCell() {
+		t.Fatal("Dead cell not found")
+	}
+	var dead *DeadCellsError
+	if err := s.Solve(); !errors.As(err, &dead) || !dead.Cells.Contains(s.Cell(0, 1)) {
+		t.Errorf("Got error %v, want the dead cell reported", err)
+	}
+	if len(s.Steps()) != 0 {
+		t.Error("Solve made progress with a dead cell")
+	}
+}
+
+func TestSolveRejectsFullInvalidBoard(t *testing.T) {
+	grid := solutionOf(t, loadPuzzle(t, "medium.txt"))
+	grid[0][0], grid[0][1] = grid[0][1], grid[0][0]
+	s := blankSudoku(t)
+	for _, cell := range s.Cells() {
+		cell.value, cell.moves = grid[cell.row][cell.col], empty
+	}
+
+	if s.IsSolved() {
+		t.Error("Board with swapped values is solved")
+	}
+	if err := s.Solve(); err != ErrInconsistent {
+		t.Errorf("Got error %v, want %v", err, ErrInconsistent)
+	}
+}
+
+func TestSolveChecksBoardFilledByTechniques(t *testing.T) {
+	// A faulty technique that overwrites a value as it places the last one
+	s := loadPuzzle(t, "medium.txt")
+	s.onStep = func(Step) {
+		if len(s.Cells().UnsetOnly()) == 0 {
+			s.board[0][1].value = s.board[0][0].value
+		}
+	}
+
+	if err := s.Solve(); err != ErrInconsistent {
+		t.Errorf("Got error %v, want %v", err, ErrInconsistent)
+	}
+}
+
+func TestValidateState(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if err := s.ValidateState(); err != nil {
+		t.Fatalf("Fresh board failed: %v", err)
+	}
+
+	// Row 1 column 2 is blank, next to the 3 in column 1
+	cell := s.Cell(0, 1)
+	cell.EliminateMove(9)
+	want := cell.moves
+	cell.moves.Add(3)
+	err := s.ValidateState()
+	if err == nil || !strings.HasPrefix(err.Error(), "Row 1 column 2 ") {
+		t.Errorf("Got error %v, want one for row 1 column 2", err)
+	}
+
+	s.RepairState()
+	if err := s.ValidateState(); err != nil {
+		t.Errorf("Repaired board failed: %v", err)
+	}
+	if cell.moves != want {
+		t.Errorf("Repaired to %v, want %v", cell.moves.Slice(), want.Slice())
+	}
+}
+
+func TestValidateStateFilledCell(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	s.Cell(0, 0).moves = movesOf(3)
+
+	err := s.ValidateState()
+	if err == nil || !strings.HasPrefix(err.Error(), "Row 1 column 1 ") {
+		t.Errorf("Got error %v, want one for row 1 column 1", err)
+	}
+	s.RepairState()
+	if got := s.Cell(0, 0).moves; got != empty || s.Cell(0, 0).value != 3 {
+		t.Errorf("Repaired to %d with candidates %v", s.Cell(0, 0).value, got.Slice())
+	}
+}
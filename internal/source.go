@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type PuzzleSource interface {
+	Puzzles() ([]*Sudoku, error)
+}
+
+type ReaderSource struct {
+	Reader  io.Reader
+	Options []ParseOption
+}
+
+func (r ReaderSource) Puzzles() ([]*Sudoku, error) {
+	return readPuzzles(r.Reader, r.Options)
+}
+
+type StringSource struct {
+	Text    string
+	Options []ParseOption
+}
+
+func (s StringSource) Puzzles() ([]*Sudoku, error) {
+	return readPuzzles(strings.NewReader(s.Text), s.Options)
+}
+
+type FileSource struct {
+	Path    string
+	Options []ParseOption
+}
+
+func (f FileSource) Puzzles() ([]*Sudoku, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readPuzzles(file, f.Options)
+}
+
+func readPuzzles(reader io.Reader, options []ParseOption) ([]*Sudoku, error) {
+	p := newParser(options)
+	boards, err := p.parseAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	puzzles := make([]*Sudoku, 0, len(boards))
+	for i, board := range boards {
+		s, err := newSudokuFromPuzzle(board, p)
+		if err != nil {
+			return puzzles, fmt.Errorf("Puzzle %d: %v", i+1, err)
+		}
+		puzzles = append(puzzles, s)
+	}
+	return puzzles, nil
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSourcesAgree(t *testing.T) {
+	path := filepath.Join("..", "puzzles", "medium.txt")
+	text, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources := map[string]PuzzleSource{
+		"file":   FileSource{Path: path},
+		"string": StringSource{Text: string(text)},
+		"reader": ReaderSource{Reader: strings.NewReader(string(text))},
+	}
+
+	want := loadPuzzle(t, "medium.txt").Grid()
+	for name, source := range sources {
+		puzzles, err := source.Puzzles()
+		if err != nil {
+			t.Errorf("The %s source failed: %v", name, err)
+			continue
+		}
+		if len(puzzles) != 1 || puzzles[0].Grid() != want {
+			t.Errorf("The %s source returned %v", name, puzzles)
+		}
+	}
+}
+
+func TestSourceReadsEveryPuzzle(t *testing.T) {
+	first := loadPuzzle(t, "medium.txt")
+	second := first.Transpose()
+
+	puzzles, err := StringSource{
+		Text:    first.String() + "\n" + second.String() + "\n",
+		Options: []ParseOption{SingleLine()},
+	}.Puzzles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(puzzles) != 2 || puzzles[0].Grid() != first.Grid() || puzzles[1].Grid() != second.Grid() {
+		t.Errorf("Got %v", puzzles)
+	}
+}
+
+func TestSourceReportsPuzzleNumber(t *testing.T) {
+	bad := "11" + strings.Repeat(".", cellCount-2)
+	_, err := StringSource{Text: singleLine("") + "\n" + bad, Options: []ParseOption{SingleLine()}}.Puzzles()
+	if err == nil || !strings.HasPrefix(err.Error(), "Puzzle 2:") {
+		t.Errorf("Got error %v, want one for puzzle 2", err)
+	}
+}
+
+func TestMissingFileSource(t *testing.T) {
+	if _, err := (FileSource{Path: filepath.Join("..", "puzzles", "missing.txt")}).Puzzles(); !os.IsNotExist(err) {
+		t.Errorf("Got error %v, want a missing file", err)
+	}
+}
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Action int
+
+const (
+	Place Action = iota
+	Eliminate
+
+	actionCount = iota
+)
+
+func (a Action) String() string {
+	switch a {
+	case Place:
+		return "place"
+	case Eliminate:
+		return "eliminate"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// Step is one placement or elimination made while solving. The Cells of an
+// elimination are exactly the cells that lost Value as a candidate.
+type Step struct {
+	Action    Action
+	Technique string
+	Cells     Cells
+	Value     int
+	Message   string
+}
+
+// Describe states exactly what the step changed, such as
+// "pointing pair: remove 4 from (1,2), (1,3)".
+func (step Step) Describe() string {
+	if step.Action == Place {
+		return fmt.Sprintf("%s: place %d at %s", step.Technique, step.Value, step.Cells.LocationString())
+	}
+	return fmt.Sprintf("%s: remove %d from %s", step.Technique, step.Value, step.Cells.LocationString())
+}
+
+func (s *Sudoku) Steps() []Step {
+	steps := make([]Step, len(s.steps))
+	copy(steps, s.steps)
+	return steps
+}
+
+func (s *Sudoku) placeStep(technique string, cell *Cell, value int, format string, args ...interface{}) error {
+	s.checkPlacement(technique, cell, value)
+	if err := s.PlayMove(cell.row, cell.col, value); err != nil {
+		return err
+	}
+
+	s.record(Step{
+		Action:    Place,
+		Technique: technique,
+		Cells:     Cells{cell},
+		Value:     value,
+		Message:   fmt.Sprintf(s.tr(format), args...),
+	})
+	return nil
+}
+
+// eliminateStep removes value from the candidates of cells, all of which must
+// still hold it, so that the step records exactly what changed.
+func (s *Sudoku) eliminateStep(technique string, cells Cells, value int, format string, args ...interface{}) {
+	s.checkElimination(technique, cells, value)
+	s.lock()
+	cells.EliminateMove(value)
+	s.unlock()
+
+	s.record(Step{
+		Action:    Eliminate,
+		Technique: technique,
+		Cells:     cells,
+		Value:     value,
+		Message:   fmt.Sprintf(s.tr(format), args...),
+	})
+}
+
+// SetStepOutput routes the messages of steps with the given action to w, for
+// example to silence eliminations while keeping placements. By default steps
+// are written to the same output as the rest of the solve.
+func (s *Sudoku) SetStepOutput(action Action, w io.Writer) {
+	s.stepOut[action] = w
+}
+
+func (s *Sudoku) stepOutput(action Action) io.Writer {
+	if w := s.stepOut[action]; w != nil {
+		return w
+	}
+	return s.output()
+}
+
+func (s *Sudoku) record(step Step) {
+	s.steps = append(s.steps, step)
+	fmt.Fprintln(s.stepOutput(step.Action), step.Message)
+	if s.onStep != nil {
+		s.onStep(step)
+	}
+}
+
+func (s *Sudoku) WriteSolutionMarkdown(w io.Writer) error {
+	clone := s.quietClone()
+	solveErr := clone.Solve()
+
+	var md strings.Builder
+	md.WriteString("## Puzzle\n\n```")
+	s.WriteBoard(&md)
+	md.WriteString("```\n\n## Steps\n\n")
+
+	for i, step := range clone.steps {
+		fmt.Fprintf(&md, "%d. **%s** %s: %s\n", i+1, step.Technique, step.Cells.LocationString(), step.Message)
+	}
+
+	if solveErr != nil {
+		fmt.Fprintf(&md, "\n## Unsolved\n\n%s\n\n```", solveErr)
+	} else {
+		md.WriteString("\n## Solution\n\n```")
+	}
+	clone.WriteBoard(&md)
+	md.WriteString("```\n")
+
+	if _, err := io.WriteString(w, md.String()); err != nil {
+		return err
+	}
+	return solveErr
+}
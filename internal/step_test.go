@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var markdownStep = regexp.MustCompile(`(?m)^\d+\. \*\*`)
+
+func TestWriteSolutionMarkdown(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	steps, err := s.SolveLength()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var md strings.Builder
+	if err := s.WriteSolutionMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(markdownStep.FindAllString(md.String(), -1)); got != steps {
+		t.Errorf("Got %d steps, want %d:\n%s", got, steps, md.String())
+	}
+	if !strings.Contains(md.String(), "## Solution") {
+		t.Errorf("No solution section:\n%s", md.String())
+	}
+	if len(s.Cells().UnsetOnly()) == 0 || len(s.steps) != 0 {
+		t.Error("WriteSolutionMarkdown changed the puzzle")
+	}
+}
+
+func TestWriteSolutionMarkdownUnsolved(t *testing.T) {
+	var md strings.Builder
+	if err := loadPuzzle(t, "blank.txt").WriteSolutionMarkdown(&md); err != ErrMultipleSolutions {
+		t.Errorf("Got error %v, want %v", err, ErrMultipleSolutions)
+	}
+	if !strings.Contains(md.String(), "## Unsolved") {
+		t.Errorf("No unsolved section:\n%s", md.String())
+	}
+}
+
+func TestSetStepOutput(t *testing.T) {
+	s := loadPuzzle(t, "hard1.txt")
+	var out, eliminations strings.Builder
+	s.SetOutput(&out)
+	s.SetStepOutput(Eliminate, &eliminations)
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := [actionCount]int{}
+	for _, step := range s.Steps() {
+		counts[step.Action]++
+		inOut := strings.Contains(out.String(), step.Message+"\n")
+		inEliminations := strings.Contains(eliminations.String(), step.Message+"\n")
+		if inOut == inEliminations || inEliminations != (step.Action == Eliminate) {
+			t.Errorf("%v step %q written to the wrong output", step.Action, step.Message)
+		}
+	}
+	if counts[Place] == 0 || counts[Eliminate] == 0 {
+		t.Fatalf("Got %d placements and %d eliminations, want both", counts[Place], counts[Eliminate])
+	}
+}
+
+func TestHelpersDoNotWriteSteps(t *testing.T) {
+	s := loadPuzzle(t, "hard1.txt")
+	var steps strings.Builder
+	s.SetStepOutput(Place, &steps)
+	s.SetStepOutput(Eliminate, &steps)
+
+	s.NextTechnique()
+	s.PropagationDepth()
+	s.SolveLength()
+	s.WriteSolutionMarkdown(io.Discard)
+	if _, err := s.Solved(); err != nil {
+		t.Fatal(err)
+	}
+	if steps.Len() != 0 {
+		t.Errorf("Solving a copy wrote steps:\n%s", steps.String())
+	}
+}
+
+func TestDescribeElimination(t *testing.T) {
+	// The 1 of the top left square can only go in its top row, and (1,5)
+	// has already lost it
+	s := blankSudoku(t)
+	s.Square(0, 0).Excluding(s.Row(0)).EliminateMove(1)
+	s.Cell(0, 4).EliminateMove(1)
+	if s.pointingPairs() != 1 {
+		t.Fatal("No pointing pair")
+	}
+
+	step := s.Steps()[0]
+	if got := step.Cells.LocationString(); got != "(1,4), (1,6), (1,7), (1,8), (1,9)" {
+		t.Errorf("Removed from %s", got)
+	}
+	if got, want := step.Describe(), "pointing pair: remove 1 from (1,4), (1,6), (1,7), (1,8), (1,9)"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
+
+func TestDescribePlacement(t *testing.T) {
+	step := Step{Action: Place, Technique: "naked single", Cells: Cells{blankSudoku(t).Cell(2, 3)}, Value: 6}
+	if got, want := step.Describe(), "naked single: place 6 at (3,4)"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
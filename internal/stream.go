@@ -0,0 +1,45 @@
+package internal
+
+import "context"
+
+// SolveStream solves the puzzle in a new goroutine and sends each step on the
+// returned channel as it is taken. The channel is closed when the solve ends,
+// after which StreamErr returns its error. Cancelling ctx stops the solve
+// after the current technique; StreamErr then returns ctx.Err(). The board
+// must not be used by other goroutines until the channel is closed.
+func (s *Sudoku) SolveStream(ctx context.Context) <-chan Step {
+	steps := make(chan Step)
+	s.ctx = ctx
+	s.onStep = func(step Step) {
+		select {
+		case steps <- step:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(steps)
+		_, err := s.SolveDetailed()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		s.streamErr = err
+		s.ctx = nil
+		s.onStep = nil
+	}()
+
+	return steps
+}
+
+// StreamErr returns the error that ended the last SolveStream, once its
+// channel has been closed.
+func (s *Sudoku) StreamErr() error {
+	return s.streamErr
+}
+
+func (s *Sudoku) cancelled() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Err()
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSolveStream(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	want, err := s.SolveLength()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for step := range s.SolveStream(context.Background()) {
+		if step.Technique == "" {
+			t.Errorf("Step %d has no technique", count+1)
+		}
+		count++
+	}
+	if count != want {
+		t.Errorf("Got %d steps, want %d", count, want)
+	}
+	if err := s.StreamErr(); err != nil || !s.IsSolved() {
+		t.Errorf("Stream ended with %v:\n%v", err, s)
+	}
+}
+
+func TestSolveStreamCancel(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := s.SolveStream(ctx)
+
+	<-steps
+	cancel()
+	for range steps {
+	}
+
+	if err := s.StreamErr(); err != context.Canceled {
+		t.Errorf("Got error %v, want %v", err, context.Canceled)
+	}
+	if s.IsSolved() {
+		t.Error("Solve ran to the end after cancelling")
+	}
+}
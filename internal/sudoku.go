@@ -1,13 +1,21 @@
 package internal
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"regexp"
 	"strings"
+	"sync"
+)
+
+// The solver only supports standard boards, but spells out the dimensions so
+// the assumptions are easy to find.
+const (
+	BoxSize   = 3
+	Size      = BoxSize * BoxSize
+	cellCount = Size * Size
 )
 
 var (
@@ -19,61 +27,191 @@ var (
 		{"bottom left", "bottom center", "bottom right"},
 	}
 
-	nondigits = regexp.MustCompile(`[^1-9 ]`)
+	ErrNoSolution        = errors.New("No solution found")
+	ErrMultipleSolutions = errors.New("Puzzle has multiple solutions")
+	ErrInconsistent      = errors.New("Board is inconsistent")
+)
+
+type SolveResult int
+
+const (
+	Solved SolveResult = iota
+	RequiresGuessing
+	Contradiction
+	MultipleSolutions
 )
 
+func (r SolveResult) String() string {
+	switch r {
+	case Solved:
+		return "solved"
+	case RequiresGuessing:
+		return "requires guessing"
+	case Contradiction:
+		return "contradiction"
+	case MultipleSolutions:
+		return "multiple solutions"
+	}
+	return fmt.Sprintf("SolveResult(%d)", int(r))
+}
+
 type Sudoku struct {
-	board [9][9]Cell
+	board    [Size][Size]Cell
+	steps    []Step
+	rounds   int
+	format   Format
+	metadata Metadata
+	out      io.Writer
+	stepOut  [actionCount]io.Writer
+	mu       *sync.RWMutex
+
+	branchStrategy func(Cells) *Cell
+	scanOrder      ScanOrder
+
+	// Known solution that deductions are checked against, see EnableSelfCheck
+	selfCheck *[Size][Size]int
+
+	// Group states already scanned by each technique, see unchanged
+	scanned map[scanKey]groupState
+
+	// In diagnostic mode, cells left without candidates do not abort the
+	// solve; they are collected and reported once no more progress is made.
+	diagnostic bool
+
+	// Translations of the text written while solving, see SetMessages
+	messages Messages
+
+	// Set while SolveStream runs
+	ctx       context.Context
+	onStep    func(Step)
+	streamErr error
+
+	// Whether Solve may guess once logic is stuck, see SetGuessing
+	guessing bool
+
+	// Whether uniqueness techniques may run before the solution is known to
+	// be unique, see SetAssumeUnique
+	assumeUnique bool
 }
 
-func NewSudokuFromReader(reader io.Reader) (*Sudoku, error) {
-	var b = [9][9]int{}
-	row := 0
-	scanner := bufio.NewScanner(reader)
+type DeadCellsError struct {
+	Cells Cells
+}
 
-	for scanner.Scan() && row < 9 {
-		line := nondigits.ReplaceAllString(scanner.Text(), "")
-		if len(line) > 0 {
-			for col := 0; col < 9 && col < len(line); col++ {
-				if line[col] != ' ' {
-					b[row][col] = int(line[col]) - '0'
-				}
-			}
-			row++
-		}
+func (e *DeadCellsError) Error() string {
+	return fmt.Sprintf("No moves left at squares %s", e.Cells.LocationString())
+}
+
+// StuckError reports that the techniques stopped making progress with cells
+// left to fill. Result tells whether the puzzle still has a solution that a
+// missing technique could find (RequiresGuessing), or none at all
+// (Contradiction). It matches ErrNoSolution with errors.Is.
+type StuckError struct {
+	Result    SolveResult
+	Remaining int
+}
+
+func (e *StuckError) Error() string {
+	if e.Result == Contradiction {
+		return fmt.Sprintf("No solution exists; stuck with %d cells left", e.Remaining)
 	}
+	return fmt.Sprintf("Stuck with %d cells left; the puzzle has a solution but needs a technique the solver does not know", e.Remaining)
+}
+
+func (e *StuckError) Unwrap() error {
+	return ErrNoSolution
+}
+
+// givenError records which given could not be placed while loading a board.
+type givenError struct {
+	row int
+	col int
+	err error
+}
+
+func (e *givenError) Error() string {
+	return e.err.Error()
+}
+
+func (e *givenError) Unwrap() error {
+	return e.err
+}
 
-	if err := scanner.Err(); err != nil {
+func NewSudokuFromReader(reader io.Reader, options ...ParseOption) (*Sudoku, error) {
+	p := newParser(options)
+	b, err := p.parse(reader)
+	if err != nil {
 		return nil, err
 	}
 
-	s, err := NewSudoku(b)
-
-	fmt.Println("Initialized Board")
-	s.PrintBoard()
-	s.PrintMoves()
+	s, err := newSudokuFromPuzzle(b, p)
+	s.printInitialized()
 
 	return s, err
 }
 
-func NewSudokuFromString(board string) (*Sudoku, error) {
-	return NewSudokuFromReader(strings.NewReader(board))
+// LoadString replaces the puzzle with one parsed from board, keeping settings
+// such as the output writers and scan order. The steps of the old puzzle are
+// discarded, along with its metadata and the solution given to
+// EnableSelfCheck. If the new puzzle cannot be loaded the board is left
+// unchanged.
+func (s *Sudoku) LoadString(board string, options ...ParseOption) error {
+	p := newParser(options)
+	b, err := p.parse(strings.NewReader(board))
+	if err != nil {
+		return err
+	}
+
+	loaded, err := newSudokuFromPuzzle(b, p)
+	if err != nil {
+		return err
+	}
+
+	s.lock()
+	s.board = loaded.board
+	s.format = loaded.format
+	s.metadata = loaded.metadata
+	s.steps = nil
+	s.rounds = 0
+	s.scanned = nil
+	// The known solution belongs to the old puzzle
+	s.selfCheck = nil
+	if p.out != nil {
+		s.out = p.out
+	}
+	s.unlock()
+
+	s.printInitialized()
+	return nil
+}
+
+func (s *Sudoku) printInitialized() {
+	fmt.Fprintln(s.output(), s.tr("Initialized Board"))
+	s.WriteBoard(s.output())
+	s.WriteMoves(s.output())
+	for _, warning := range s.Warnings() {
+		fmt.Fprintf(s.output(), s.tr("Warning: %s")+"\n", warning)
+	}
 }
 
-func NewSudokuFromFile(path string) (*Sudoku, error) {
+func NewSudokuFromString(board string, options ...ParseOption) (*Sudoku, error) {
+	return NewSudokuFromReader(strings.NewReader(board), options...)
+}
+
+func NewSudokuFromFile(path string, options ...ParseOption) (*Sudoku, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-	return NewSudokuFromReader(f)
+	return NewSudokuFromReader(f, options...)
 }
 
-func NewSudoku(board [9][9]int) (*Sudoku, error) {
+func newEmptySudoku() *Sudoku {
 	s := &Sudoku{}
 
-	for row := 0; row < 9; row++ {
-		for col := 0; col < 9; col++ {
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
 			s.board[row][col] = Cell{
 				row:   row,
 				col:   col,
@@ -83,30 +221,150 @@ func NewSudoku(board [9][9]int) (*Sudoku, error) {
 		}
 	}
 
-	for row := 0; row < 9; row++ {
-		for col := 0; col < 9; col++ {
+	return s
+}
+
+func NewSudoku(board [Size][Size]int) (*Sudoku, error) {
+	s := newEmptySudoku()
+
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
 			value := board[row][col]
 			if value != 0 {
 				if err := s.PlayMove(row, col, value); err != nil {
-					return s, err
+					return s, &givenError{row: row, col: col, err: err}
 				}
+				s.board[row][col].given = true
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// RejectedGiven is a given that NewSudokuLenient could not place, with the
+// reason it was rejected. Rows and columns count from zero.
+type RejectedGiven struct {
+	Row   int
+	Col   int
+	Value int
+	Err   error
+}
+
+// NewSudokuLenient loads a board like NewSudoku, but keeps going past givens
+// that conflict with those already placed. Every given that fits is placed,
+// and the rest are returned so they can all be reported and fixed at once.
+// Cells left without candidates are not treated as conflicts; they show up
+// in DeadCells instead.
+func NewSudokuLenient(board [Size][Size]int) (*Sudoku, []RejectedGiven) {
+	s := newEmptySudoku()
+	s.diagnostic = true
+	rejected := make([]RejectedGiven, 0)
+
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
+			value := board[row][col]
+			if value == 0 {
+				continue
 			}
+			if err := s.PlayMove(row, col, value); err != nil {
+				rejected = append(rejected, RejectedGiven{Row: row, Col: col, Value: value, Err: err})
+				continue
+			}
+			s.board[row][col].given = true
 		}
 	}
 
+	s.diagnostic = false
+	return s, rejected
+}
+
+// Clue is a given value at a cell. Rows and columns count from zero.
+type Clue struct {
+	Row   int
+	Col   int
+	Value int
+}
+
+// NewSudokuFromClues builds a board by placing the clues as givens in order.
+// Placing stops at the first clue that is out of range or conflicts with
+// those before it, and the error names its index in clues.
+func NewSudokuFromClues(clues []Clue) (*Sudoku, error) {
+	s := newEmptySudoku()
+
+	for i, clue := range clues {
+		if err := s.PlayMove(clue.Row, clue.Col, clue.Value); err != nil {
+			return s, fmt.Errorf("Clue %d: %v", i, err)
+		}
+		s.board[clue.Row][clue.Col].given = true
+	}
+
 	return s, nil
 }
 
+// No puzzle with fewer clues than this has a unique solution.
+const minClues = 17
+
+// DigitCounts counts how often each value is placed on the board, indexed by
+// value. Index 0 counts the empty cells.
+func (s *Sudoku) DigitCounts() [Size + 1]int {
+	counts := [Size + 1]int{}
+	for _, cell := range s.Cells() {
+		counts[cell.value]++
+	}
+	return counts
+}
+
+// ClueDistribution counts the givens in each band, the three rows of squares
+// from top to bottom, and in each stack, the three columns of squares from
+// left to right.
+func (s *Sudoku) ClueDistribution() (bands [BoxSize]int, stacks [BoxSize]int) {
+	for _, cell := range s.Cells() {
+		if cell.given {
+			bands[cell.row/BoxSize]++
+			stacks[cell.col/BoxSize]++
+		}
+	}
+	return bands, stacks
+}
+
+// Warnings reports problems with the puzzle that do not prevent loading it.
+func (s *Sudoku) Warnings() []string {
+	warnings := make([]string, 0)
+
+	clues := 0
+	for _, cell := range s.Cells() {
+		if cell.given {
+			clues++
+		}
+	}
+	if clues < minClues {
+		warnings = append(warnings, fmt.Sprintf(s.tr("Only %d clues given; a puzzle needs at least %d to have a unique solution"), clues, minClues))
+	}
+
+	return warnings
+}
+
 func (s *Sudoku) Cells() Cells {
-	return s.Range(0, 0, 8, 8)
+	return s.Range(0, 0, Size-1, Size-1)
+}
+
+// Each calls fn with the position and value of every cell in row-major order,
+// with 0 for blank cells. Unlike Cells, it allocates nothing.
+func (s *Sudoku) Each(fn func(row, col int, value int)) {
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
+			fn(row, col, s.board[row][col].value)
+		}
+	}
 }
 
 func (s *Sudoku) Row(row int) Cells {
-	return s.Range(row, 0, row, 8)
+	return s.Range(row, 0, row, Size-1)
 }
 
 func (s *Sudoku) Col(col int) Cells {
-	return s.Range(0, col, 8, col)
+	return s.Range(0, col, Size-1, col)
 }
 
 func (s *Sudoku) Cell(row, col int) *Cell {
@@ -114,11 +372,35 @@ func (s *Sudoku) Cell(row, col int) *Cell {
 }
 
 func (s *Sudoku) Square(row, col int) Cells {
-	return s.Range(row*3, col*3, row*3+2, col*3+2)
+	return s.Range(row*BoxSize, col*BoxSize, row*BoxSize+BoxSize-1, col*BoxSize+BoxSize-1)
+}
+
+func (s *Sudoku) SquareByIndex(i int) Cells {
+	if i < 0 || i >= Size {
+		panic(fmt.Errorf("square index out of range: %d", i))
+	}
+	return s.Square(i/BoxSize, i%BoxSize)
+}
+
+// SquareName names a square by its position on the board, such as top left.
+func (s *Sudoku) SquareName(boxRow, boxCol int) string {
+	return s.tr(positionNames[boxRow][boxCol])
+}
+
+// RowName names the position of a row within its square: top, center or
+// bottom.
+func (s *Sudoku) RowName(row int) string {
+	return s.tr(rowPositionNames[row%BoxSize])
+}
+
+// ColName names the position of a column within its square: left, center or
+// right.
+func (s *Sudoku) ColName(col int) string {
+	return s.tr(colPositionNames[col%BoxSize])
 }
 
 func (s *Sudoku) Range(top, left, bottom, right int) Cells {
-	cells := make(Cells, 0, 9)
+	cells := make(Cells, 0, Size)
 	for row := top; row <= bottom; row++ {
 		for col := left; col <= right; col++ {
 			cells = append(cells, &s.board[row][col])
@@ -128,170 +410,605 @@ func (s *Sudoku) Range(top, left, bottom, right int) Cells {
 }
 
 func (s *Sudoku) Rows() []Cells {
-	rows := make([]Cells, 0, 9)
-	for i := 0; i < 9; i++ {
+	rows := make([]Cells, 0, Size)
+	for i := 0; i < Size; i++ {
 		rows = append(rows, s.Row(i))
 	}
 	return rows
 }
 
 func (s *Sudoku) Cols() []Cells {
-	cols := make([]Cells, 0, 9)
-	for i := 0; i < 9; i++ {
+	cols := make([]Cells, 0, Size)
+	for i := 0; i < Size; i++ {
 		cols = append(cols, s.Col(i))
 	}
 	return cols
 }
 
 func (s *Sudoku) Squares() []Cells {
-	squares := make([]Cells, 0, 9)
-	for row := 0; row < 3; row++ {
-		for col := 0; col < 3; col++ {
-			squares = append(squares, s.Square(row, col))
-		}
+	squares := make([]Cells, 0, Size)
+	for i := 0; i < Size; i++ {
+		squares = append(squares, s.SquareByIndex(i))
 	}
 	return squares
 }
 
+// Groups returns all 27 groups of the board: the nine rows from top to bottom,
+// then the nine columns from left to right, then the nine squares in row-major
+// order. Techniques scan groups in this order unless a different ScanOrder is
+// set, so changing it changes the order in which steps are found.
 func (s *Sudoku) Groups() []Cells {
 	return append(append(s.Rows(), s.Cols()...), s.Squares()...)
 }
 
+// GroupsOf returns the groups that contain a cell: its row, its column and
+// its square, in the same order as Groups.
+func (s *Sudoku) GroupsOf(row, col int) []Cells {
+	return []Cells{s.Row(row), s.Col(col), s.Square(row/BoxSize, col/BoxSize)}
+}
+
+// Sees reports whether two cells share a row, column or square. A cell does
+// not see itself.
+func (s *Sudoku) Sees(r1, c1, r2, c2 int) bool {
+	return sees(s.Cell(r1, c1), s.Cell(r2, c2))
+}
+
+func sees(a, b *Cell) bool {
+	if a.row == b.row && a.col == b.col {
+		return false
+	}
+	return a.row == b.row || a.col == b.col ||
+		(a.row/BoxSize == b.row/BoxSize && a.col/BoxSize == b.col/BoxSize)
+}
+
 func (s *Sudoku) Clone() *Sudoku {
 	return &Sudoku{
-		board: s.board,
+		board:          s.board,
+		format:         s.format,
+		metadata:       s.metadata,
+		out:            s.out,
+		stepOut:        s.stepOut,
+		branchStrategy: s.branchStrategy,
+		scanOrder:      s.scanOrder,
+		selfCheck:      s.selfCheck,
+		diagnostic:     s.diagnostic,
+		guessing:       s.guessing,
+		assumeUnique:   s.assumeUnique,
+		messages:       s.messages,
+	}
+}
+
+// quietClone returns a clone that writes nothing, not even to the writers
+// set with SetStepOutput, for working on a copy out of the user's sight.
+func (s *Sudoku) quietClone() *Sudoku {
+	clone := s.Clone()
+	clone.SetOutput(io.Discard)
+	clone.stepOut = [actionCount]io.Writer{}
+	return clone
+}
+
+func (s *Sudoku) Format() Format {
+	return s.format
+}
+
+func (s *Sudoku) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+func (s *Sudoku) SetDiagnostic(enabled bool) {
+	s.diagnostic = enabled
+}
+
+// SetGuessing lets Solve finish puzzles that have a unique solution but need
+// a technique the solver does not know. Search is strictly the last resort:
+// it is only used once no technique makes progress, and only for one cell
+// before the techniques are tried again. Puzzles that logic can solve are
+// never guessed at. Guessing is off by default.
+func (s *Sudoku) SetGuessing(enabled bool) {
+	s.guessing = enabled
+}
+
+// SetAssumeUnique lets Solve use the uniqueness techniques from the start. By
+// default they only run once logic is stuck and search has confirmed there is
+// a single solution, since on a puzzle with several they pick one at random.
+// Set this only for puzzles known to be unique, such as generated ones.
+func (s *Sudoku) SetAssumeUnique(enabled bool) {
+	s.assumeUnique = enabled
+}
+
+// maxTier is the hardest tier that is safe to use before uniqueness has been
+// checked.
+func (s *Sudoku) maxTier() Tier {
+	if s.assumeUnique {
+		return TierUniqueness
 	}
+	return TierChains
+}
+
+// safeTier is like maxTier, but checks by search whether the puzzle is unique
+// so that the uniqueness techniques can be used when it is.
+func (s *Sudoku) safeTier() Tier {
+	if s.assumeUnique || s.CountSolutions(2) == 1 {
+		return TierUniqueness
+	}
+	return TierChains
+}
+
+func (s *Sudoku) output() io.Writer {
+	if s.out == nil {
+		return os.Stdout
+	}
+	return s.out
 }
 
 func (s *Sudoku) PlayMove(row int, col int, value int) error {
-	if row < 0 || row >= 9 {
+	if row < 0 || row >= Size {
 		return fmt.Errorf("Row %d out of bounds", row+1)
 	}
-	if col < 0 || col >= 9 {
+	if col < 0 || col >= Size {
 		return fmt.Errorf("Col %d out of bounds", col+1)
 	}
-	if value < 1 || value > 9 {
+	if value < 1 || value > Size {
 		return fmt.Errorf("Value %d out of bounds", col+1)
 	}
 
 	if s.Cell(row, col).value != 0 {
-		return fmt.Errorf("Cell %d,%d already contains %d", row+1, col+1, s.board[row][col])
+		return fmt.Errorf("Cell %d,%d already contains %d", row+1, col+1, s.board[row][col].value)
 	}
 
-	if !s.Row(row).RemainingMoves().Contains(value) {
+	if remaining := s.Row(row).RemainingMoves(); !remaining.Contains(value) {
 		return fmt.Errorf("Row %d already contains %d", row+1, value)
 	}
-	if !s.Col(col).RemainingMoves().Contains(value) {
+	if remaining := s.Col(col).RemainingMoves(); !remaining.Contains(value) {
 		return fmt.Errorf("Col %d already contains %d", col+1, value)
 	}
-	squareRow, squareCol := row/3, col/3
-	if !s.Square(squareRow, squareCol).RemainingMoves().Contains(value) {
-		return fmt.Errorf("The %s square already contains %d", positionNames[squareRow][squareCol], value)
+	squareRow, squareCol := row/BoxSize, col/BoxSize
+	if remaining := s.Square(squareRow, squareCol).RemainingMoves(); !remaining.Contains(value) {
+		return fmt.Errorf("The %s square already contains %d", s.SquareName(squareRow, squareCol), value)
 	}
 	if !s.Cell(row, col).CanPlay(value) {
 		return fmt.Errorf("Cell %d,%d is not a valid spot for %d", row+1, col+1, value)
 	}
 
+	s.lock()
 	err := s.Cell(row, col).Set(value)
+	if err == nil {
+		s.Row(row).EliminateMove(value)
+		s.Col(col).EliminateMove(value)
+		s.Square(row/BoxSize, col/BoxSize).EliminateMove(value)
+	}
+	s.unlock()
 	if err != nil {
 		return err
 	}
-	s.Row(row).EliminateMove(value)
-	s.Col(col).EliminateMove(value)
-	s.Square(row/3, col/3).EliminateMove(value)
 
-	for row := 0; row < 9; row++ {
-		for col := 0; col < 9; col++ {
-			cell := s.Cell(row, col)
-			if cell.value == 0 && cell.moves == empty {
-				return fmt.Errorf("No moves left at square %d,%d", row+1, col+1)
-			}
+	if !s.diagnostic {
+		if dead := s.DeadCells(); len(dead) > 0 {
+			return fmt.Errorf("No moves left at square %d,%d", dead[0].row+1, dead[0].col+1)
 		}
 	}
 
 	return nil
 }
 
+func (s *Sudoku) RecomputeCandidates() {
+	for _, cell := range s.Cells() {
+		if cell.value != 0 {
+			cell.moves = empty
+			continue
+		}
+
+		cell.moves = full &^ s.ConstraintMask(cell.row, cell.col)
+	}
+}
+
+// ConstraintMask returns the values placed in the 20 peers of a cell: the
+// other cells of its row, column and square. These are the values the cell
+// cannot take.
+func (s *Sudoku) ConstraintMask(row, col int) Moves {
+	placed := empty
+	for _, group := range s.GroupsOf(row, col) {
+		for _, cell := range group {
+			if cell.value != 0 && (cell.row != row || cell.col != col) {
+				placed.Add(cell.value)
+			}
+		}
+	}
+	return placed
+}
+
+// PlacedCells returns the cells holding value. No cell can hold a value out
+// of range, so none are returned for one; in particular 0 does not match the
+// empty cells.
+func (s *Sudoku) PlacedCells(value int) Cells {
+	if value < 1 || value > Size {
+		return Cells{}
+	}
+	return s.Cells().Filter(func(cell *Cell) bool {
+		return cell.value == value
+	})
+}
+
+func (s *Sudoku) BiValueCells() Cells {
+	cells := make(Cells, 0)
+	for _, cell := range s.Cells().UnsetOnly() {
+		if cell.moves.Count() == 2 {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// MostConstrainedGroup returns the row, column or square with the fewest empty
+// cells, along with that count. Complete groups are skipped, so on a solved
+// board it returns nil and 0. Ties go to the first group in the order of
+// Groups.
+func (s *Sudoku) MostConstrainedGroup() (Cells, int) {
+	var best Cells
+	bestEmpty := 0
+	for _, group := range s.Groups() {
+		unset := len(group.UnsetOnly())
+		if unset > 0 && (best == nil || unset < bestEmpty) {
+			best, bestEmpty = group, unset
+		}
+	}
+	return best, bestEmpty
+}
+
+func (s *Sudoku) DeadCells() Cells {
+	dead := make(Cells, 0)
+	for _, cell := range s.Cells().UnsetOnly() {
+		if cell.moves == empty {
+			dead = append(dead, cell)
+		}
+	}
+	return dead
+}
+
 func (s *Sudoku) PrintBoard() {
-	fmt.Println()
-	for row := 0; row < 9; row++ {
-		if row == 3 || row == 6 {
-			fmt.Println("-----+-----+-----")
-		}
-		for col := 0; col < 9; col++ {
-			if col == 3 || col == 6 {
-				fmt.Print("|")
+	s.WriteBoard(os.Stdout)
+}
+
+// BoardStyle sets the characters WriteBoardStyled draws between squares. A
+// zero Horizontal leaves out the lines between bands of squares, and a zero
+// Vertical separates squares with a space. A zero Cross continues the
+// horizontal line.
+type BoardStyle struct {
+	Horizontal rune
+	Vertical   rune
+	Cross      rune
+}
+
+// DefaultBoardStyle is the style of WriteBoard.
+var DefaultBoardStyle = BoardStyle{Horizontal: '-', Vertical: '|', Cross: '+'}
+
+func (s *Sudoku) WriteBoard(w io.Writer) {
+	s.WriteBoardStyled(w, DefaultBoardStyle)
+}
+
+func (s *Sudoku) WriteBoardStyled(w io.Writer, style BoardStyle) {
+	vertical, cross := style.Vertical, style.Cross
+	if vertical == 0 {
+		vertical = ' '
+	}
+	if cross == 0 {
+		cross = style.Horizontal
+	}
+	band := strings.Repeat(string(style.Horizontal), 2*BoxSize-1)
+	separator := strings.Repeat(band+string(cross), BoxSize-1) + band
+
+	fmt.Fprintln(w)
+	for row := 0; row < Size; row++ {
+		if row > 0 && row%BoxSize == 0 && style.Horizontal != 0 {
+			fmt.Fprintln(w, separator)
+		}
+		for col := 0; col < Size; col++ {
+			if col > 0 && col%BoxSize == 0 {
+				fmt.Fprint(w, string(vertical))
 			} else if col > 0 {
-				fmt.Print(" ")
+				fmt.Fprint(w, " ")
 			}
 			value := s.board[row][col].value
 			if value > 0 {
-				fmt.Print(value)
+				fmt.Fprint(w, value)
 			} else {
-				fmt.Print(" ")
+				fmt.Fprint(w, " ")
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
+}
+
+// String returns the board as a single line of 81 characters, row by row,
+// with '.' for blank cells.
+func (s *Sudoku) String() string {
+	var text strings.Builder
+	for _, cell := range s.Cells() {
+		if cell.value != 0 {
+			text.WriteByte(byte('0' + cell.value))
+		} else {
+			text.WriteByte('.')
+		}
+	}
+	return text.String()
 }
 
 func (s *Sudoku) PrintMoves() {
-	fmt.Println()
-	for row := 0; row < 9; row++ {
-		if row == 3 || row == 6 {
-			fmt.Println("-----------+-----------+-----------")
+	s.WriteMoves(os.Stdout)
+}
+
+func (s *Sudoku) MovesLayout() [Size][Size][Size]bool {
+	layout := [Size][Size][Size]bool{}
+	for _, cell := range s.Cells() {
+		for _, value := range cell.Moves() {
+			layout[cell.row][cell.col][value-1] = true
+		}
+	}
+	return layout
+}
+
+func (s *Sudoku) WriteMoves(w io.Writer) {
+	layout := s.MovesLayout()
+
+	fmt.Fprintln(w)
+	for row := 0; row < Size; row++ {
+		if row > 0 && row%BoxSize == 0 {
+			fmt.Fprintln(w, "-----------+-----------+-----------")
 		} else if row > 0 {
-			fmt.Println("           |           |           ")
+			fmt.Fprintln(w, "           |           |           ")
 		}
 
-		for moveRow := 0; moveRow < 3; moveRow++ {
-			for col := 0; col < 9; col++ {
-				if col == 3 || col == 6 {
-					fmt.Print("|")
+		for moveRow := 0; moveRow < BoxSize; moveRow++ {
+			for col := 0; col < Size; col++ {
+				if col > 0 && col%BoxSize == 0 {
+					fmt.Fprint(w, "|")
 				} else if col > 0 {
-					fmt.Print(" ")
+					fmt.Fprint(w, " ")
 				}
 
-				for value := moveRow*3 + 1; value < moveRow*3+4; value++ {
-					if s.Cell(row, col).CanPlay(value) {
-						fmt.Print(value)
+				for value := moveRow*BoxSize + 1; value <= moveRow*BoxSize+BoxSize; value++ {
+					if layout[row][col][value-1] {
+						fmt.Fprint(w, value)
 					} else {
-						fmt.Print(" ")
+						fmt.Fprint(w, " ")
 					}
 				}
 			}
-			fmt.Println()
+			fmt.Fprintln(w)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// WriteHeatmap writes the board laid out like WriteBoard, showing the number
+// of candidates left in each unset cell and '·' for filled cells. Low numbers
+// show where the puzzle is most constrained.
+func (s *Sudoku) WriteHeatmap(w io.Writer) {
+	fmt.Fprintln(w)
+	for row := 0; row < Size; row++ {
+		if row > 0 && row%BoxSize == 0 {
+			fmt.Fprintln(w, "-----+-----+-----")
+		}
+		for col := 0; col < Size; col++ {
+			if col > 0 && col%BoxSize == 0 {
+				fmt.Fprint(w, "|")
+			} else if col > 0 {
+				fmt.Fprint(w, " ")
+			}
+			cell := s.Cell(row, col)
+			if cell.value != 0 {
+				fmt.Fprint(w, "·")
+			} else {
+				fmt.Fprint(w, cell.moves.Count())
+			}
 		}
+		fmt.Fprintln(w)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 func (s *Sudoku) Solve() error {
+	_, err := s.SolveDetailed()
+	return err
+}
+
+// SolveDetailed solves the puzzle as far as logic allows and reports how it
+// went. Puzzles that are not uniquely solvable, such as an empty board, are
+// reported as MultipleSolutions with ErrMultipleSolutions rather than being
+// completed to an arbitrary solution. When logic gets stuck otherwise, the
+// error is a *StuckError, unless guessing is enabled with SetGuessing.
+// Uniqueness techniques are held back until the solution is known to be
+// unique, see SetAssumeUnique.
+func (s *Sudoku) SolveDetailed() (SolveResult, error) {
+	// Techniques assume a consistent board, and can place conflicting values
+	// on one that is not.
+	if !s.IsValid() {
+		return Contradiction, ErrInconsistent
+	}
+	if !s.diagnostic && s.HasDeadCell() {
+		return Contradiction, &DeadCellsError{Cells: s.DeadCells()}
+	}
+
+	err := s.solve(s.maxTier())
+	if err == nil {
+		return Solved, nil
+	}
+	if err != ErrNoSolution {
+		return Contradiction, err
+	}
+
+	switch s.CountSolutions(2) {
+	case 0:
+		return Contradiction, &StuckError{Result: Contradiction, Remaining: len(s.Cells().UnsetOnly())}
+	case 1:
+		// The solution is now known to be unique, so the uniqueness
+		// techniques are sound
+		if !s.assumeUnique {
+			if err := s.solve(TierUniqueness); err != ErrNoSolution {
+				if err != nil {
+					return Contradiction, err
+				}
+				return Solved, nil
+			}
+		}
+		if s.guessing {
+			return s.guessToEnd()
+		}
+		return RequiresGuessing, &StuckError{Result: RequiresGuessing, Remaining: len(s.Cells().UnsetOnly())}
+	default:
+		return MultipleSolutions, ErrMultipleSolutions
+	}
+}
+
+// guessToEnd finishes a uniquely solvable puzzle that logic is stuck on. Each
+// guess is a single correct value, recorded as a "guess" step, after which
+// the techniques get another chance; so every technique has been tried and
+// found nothing before any guess is made.
+func (s *Sudoku) guessToEnd() (SolveResult, error) {
+	solutions, err := s.Solutions(1)
+	if err != nil {
+		return Contradiction, err
+	}
+	solution := solutions[0]
+
+	err = ErrNoSolution
+	for err == ErrNoSolution {
+		cell := s.branchCell()
+		value := solution.board[cell.row][cell.col].value
+		if err := s.placeStep("guess", cell, value, "No technique applies, so guess %d at row %d column %d", value, cell.row+1, cell.col+1); err != nil {
+			return Contradiction, err
+		}
+		err = s.solve(TierUniqueness)
+	}
+	if err != nil {
+		return Contradiction, err
+	}
+	return Solved, nil
+}
+
+func (s *Sudoku) solve(max Tier) error {
+	// Nothing to do on a complete board, so no techniques are run and no
+	// steps are recorded.
+	if len(s.Cells().UnsetOnly()) == 0 {
+		return s.finish()
+	}
+	return s.solveUpTo(max)
+}
+
+// finish reports a complete board as solved, after making sure that no value
+// is repeated in a group, so that a faulty technique cannot pass off a wrong
+// solution.
+func (s *Sudoku) finish() error {
+	if !s.IsValid() {
+		return ErrInconsistent
+	}
+	fmt.Fprintln(s.output(), s.tr("Solved"))
+	s.WriteBoard(s.output())
+	return nil
+}
+
+// round applies each technique up to the given tier once, and returns the
+// number of changes they made.
+func (s *Sudoku) round(max Tier) (int, error) {
+	moves := 0
+
+	for _, technique := range techniques {
+		if technique.tier > max {
+			continue
+		}
+		if err := s.cancelled(); err != nil {
+			return moves, err
+		}
+		changes, err := technique.apply(s)
+		if err != nil {
+			return moves, err
+		}
+		moves += changes
+	}
+
+	if moves > 0 {
+		s.rounds++
+	}
+	return moves, nil
+}
+
+func (s *Sudoku) solveUpTo(max Tier) error {
+	moves, err := s.round(max)
+	if err != nil {
+		return err
+	}
+
+	if len(s.Cells().UnsetOnly()) == 0 {
+		return s.finish()
+	}
+
+	if moves > 0 {
+		s.WriteBoard(s.output())
+		s.WriteMoves(s.output())
+		return s.solveUpTo(max)
+	}
+
+	//for _, cell := range s.Cells().UnsetOnly() {
+	//	for _, value := range cell.Moves() {
+	//		row := cell.row
+	//		col := cell.col
+	//		log("Guessing number %d in row %d column %d\n", value, row+1, col+1)
+	//
+	//		clone := s.Clone()
+	//		if err := clone.PlayMove(row, col, value); err != nil {
+	//			return err
+	//		}
+	//		if err := clone.Solve(); err != nil {
+	//			log("Bad guess: %d in row %d column %d\n", value, row+1, col+1)
+	//			continue
+	//		}
+	//		return nil
+	//	}
+	//}
+
+	if s.diagnostic {
+		if dead := s.DeadCells(); len(dead) > 0 {
+			return &DeadCellsError{Cells: dead}
+		}
+	}
+
+	return ErrNoSolution
+}
+
+func (s *Sudoku) nakedSingles() (int, error) {
 	moves := 0
 
 	// Cells where only a single move is possible
-	for _, cell := range s.Cells() {
+	for _, cell := range s.scanCells() {
 		possibleMoves := cell.Moves()
 		if len(possibleMoves) == 1 {
 			value := possibleMoves[0]
-			if err := s.PlayMove(cell.row, cell.col, value); err != nil {
-				return err
+			if err := s.placeStep("naked single", cell, value, "Only %d fits in row %d column %d", value, cell.row+1, cell.col+1); err != nil {
+				return moves, err
 			}
 			moves++
-			log("Only %d fits in row %d column %d\n", value, cell.row+1, cell.col+1)
 		}
 	}
 
+	return moves, nil
+}
+
+func (s *Sudoku) hiddenSingles() (int, error) {
+	moves := 0
+
 	// Squares where a number only fits in one cell
 	for _, square := range s.Squares() {
-		for _, value := range square.RemainingMoves().Slice() {
+		if s.unchanged("hidden single", square) {
+			continue
+		}
+		remaining := square.RemainingMoves()
+		for _, value := range remaining.Slice() {
 			cells := square.FindMove(value)
 			if len(cells) == 1 {
 				cell := cells[0]
-				log("In the %s square, the number %d only fits in the %s cell\n", positionNames[cell.row/3][cell.col/3], value, positionNames[cell.row%3][cell.col%3])
-				if err := s.PlayMove(cell.row, cell.col, value); err != nil {
-					return err
+				if err := s.placeStep("hidden single", cell, value, "In the %s square, the number %d only fits in the %s cell", s.SquareName(cell.row/BoxSize, cell.col/BoxSize), value, s.tr(positionNames[cell.row%BoxSize][cell.col%BoxSize])); err != nil {
+					return moves, err
 				}
 				moves++
 			}
@@ -300,13 +1017,16 @@ func (s *Sudoku) Solve() error {
 
 	// Rows where a number only fits in one cell
 	for _, row := range s.Rows() {
-		for _, value := range row.RemainingMoves().Slice() {
+		if s.unchanged("hidden single", row) {
+			continue
+		}
+		remaining := row.RemainingMoves()
+		for _, value := range remaining.Slice() {
 			cells := row.FindMove(value)
 			if len(cells) == 1 {
 				cell := cells[0]
-				log("The %d on row %d only fits in column %d\n", value, cell.row+1, cell.col+1)
-				if err := s.PlayMove(cell.row, cell.col, value); err != nil {
-					return err
+				if err := s.placeStep("hidden single", cell, value, "The %d on row %d only fits in column %d", value, cell.row+1, cell.col+1); err != nil {
+					return moves, err
 				}
 				moves++
 			}
@@ -315,32 +1035,42 @@ func (s *Sudoku) Solve() error {
 
 	// Columns where a number only fits in one cell
 	for _, col := range s.Cols() {
-		for _, value := range col.RemainingMoves().Slice() {
+		if s.unchanged("hidden single", col) {
+			continue
+		}
+		remaining := col.RemainingMoves()
+		for _, value := range remaining.Slice() {
 			cells := col.FindMove(value)
 			if len(cells) == 1 {
 				cell := cells[0]
-				log("The %d in column %d only fits at row %d\n", value, cell.col+1, cell.row+1)
-				if err := s.PlayMove(cell.row, cell.col, value); err != nil {
-					return err
+				if err := s.placeStep("hidden single", cell, value, "The %d in column %d only fits at row %d", value, cell.col+1, cell.row+1); err != nil {
+					return moves, err
 				}
 				moves++
 			}
 		}
 	}
 
+	return moves, nil
+}
+
+func (s *Sudoku) pointingPairs() int {
+	moves := 0
+
 	// If a number can only be in one row/col in a square, eliminate the number from that row/col in aligned squares
 	for _, square := range s.Squares() {
-		squareRow := square[0].row / 3
-		squareCol := square[0].col / 3
+		squareRow := square[0].row / BoxSize
+		squareCol := square[0].col / BoxSize
 
-		for _, value := range square.RemainingMoves().Slice() {
+		remaining := square.RemainingMoves()
+		for _, value := range remaining.Slice() {
 			cells := square.FindMove(value)
 
 			rows := cells.UniqueRows()
 			if len(rows) == 1 {
 				row := rows[0]
-				if s.Row(row).Excluding(square).EliminateMove(value) > 0 {
-					log("In the %s square, the number %d only fits in the %s row\n", positionNames[squareRow][squareCol], value, rowPositionNames[row%3])
+				if excludable := s.Row(row).Excluding(square).FindMove(value); len(excludable) > 0 {
+					s.eliminateStep("pointing pair", excludable, value, "In the %s square, the number %d only fits in the %s row", s.SquareName(squareRow, squareCol), value, s.RowName(row))
 					moves++
 				}
 			}
@@ -348,26 +1078,33 @@ func (s *Sudoku) Solve() error {
 			cols := cells.UniqueCols()
 			if len(cols) == 1 {
 				col := cols[0]
-				if s.Col(col).Excluding(square).EliminateMove(value) > 0 {
-					log("In the %s square, the number %d only fits in the %s column\n", positionNames[squareRow][squareCol], value, colPositionNames[col%3])
+				if excludable := s.Col(col).Excluding(square).FindMove(value); len(excludable) > 0 {
+					s.eliminateStep("pointing pair", excludable, value, "In the %s square, the number %d only fits in the %s column", s.SquareName(squareRow, squareCol), value, s.ColName(col))
 					moves++
 				}
 			}
 		}
 	}
 
+	return moves
+}
+
+func (s *Sudoku) claiming() int {
+	moves := 0
+
 	// If a number can only be played in a single square on a row, eliminate the number from the other rows in the square
 	for _, row := range s.Rows() {
-		for _, value := range row.RemainingMoves().Slice() {
+		remaining := row.RemainingMoves()
+		for _, value := range remaining.Slice() {
 			cells := row.FindMove(value)
 			cols := cells.UniqueCols()
 			squareCols := uniqueSquares(cols)
 
 			if len(squareCols) == 1 {
-				squareRow := row[0].row / 3
+				squareRow := row[0].row / BoxSize
 				squareCol := squareCols[0]
-				if s.Square(squareRow, squareCol).Excluding(row).EliminateMove(value) > 0 {
-					log("The %d in the %s square must be in the %s row\n", value, positionNames[squareRow][squareCol], rowPositionNames[row[0].row%3])
+				if excludable := s.Square(squareRow, squareCol).Excluding(row).FindMove(value); len(excludable) > 0 {
+					s.eliminateStep("claiming", excludable, value, "The %d in the %s square must be in the %s row", value, s.SquareName(squareRow, squareCol), s.RowName(row[0].row))
 					moves++
 				}
 			}
@@ -376,38 +1113,60 @@ func (s *Sudoku) Solve() error {
 
 	// If a number can only be played in a single square in a column, eliminate the number from the other columns in the square
 	for _, col := range s.Cols() {
-		for _, value := range col.RemainingMoves().Slice() {
+		remaining := col.RemainingMoves()
+		for _, value := range remaining.Slice() {
 			cells := col.FindMove(value)
 			rows := cells.UniqueRows()
 			squareRows := uniqueSquares(rows)
 
 			if len(squareRows) == 1 {
 				squareRow := squareRows[0]
-				squareCol := col[0].col / 3
-				if s.Square(squareRow, squareCol).Excluding(col).EliminateMove(value) > 0 {
-					log("The %d in the %s square must be in the %s column\n", value, positionNames[squareRow][squareCol], colPositionNames[col[0].col%3])
+				squareCol := col[0].col / BoxSize
+				if excludable := s.Square(squareRow, squareCol).Excluding(col).FindMove(value); len(excludable) > 0 {
+					s.eliminateStep("claiming", excludable, value, "The %d in the %s square must be in the %s column", value, s.SquareName(squareRow, squareCol), s.ColName(col[0].col))
 					moves++
 				}
 			}
 		}
 	}
 
-	// Naked permutations
-	for _, group := range s.Groups() {
+	return moves
+}
+
+func (s *Sudoku) nakedSubsets() int {
+	moves := 0
+
+	// Naked permutations: n cells whose candidates together are exactly n
+	// values
+	for _, group := range s.scanGroups() {
+		if s.unchanged("naked subset", group) {
+			continue
+		}
 		group = group.UnsetOnly()
-		for _, subset := range group.PowerSet() {
-			if len(subset) < 2 || len(subset) == len(group) {
-				continue
-			}
-			remainingMoves := subset.RemainingMoves().Slice()
+		remaining := group.RemainingMoves()
+
+		// Smallest subsets first, so that a pair is not reported as part of
+		// a larger subset
+		for size := 2; size < len(group); size++ {
+			for values := remaining; values != empty; values = (values - 1) & remaining {
+				if values.Count() != size {
+					continue
+				}
+
+				subset := group.Filter(func(cell *Cell) bool {
+					return cell.moves&^values == empty
+				})
+				candidates := subset.RemainingMoves()
+				if len(subset) != size || !candidates.Equal(values) {
+					continue
+				}
 
-			if len(subset) == len(remainingMoves) {
 				otherCells := group.Excluding(subset)
-				for _, value := range remainingMoves {
+				for _, value := range values.Slice() {
 					excludable := otherCells.FindMove(value)
 					if len(excludable) > 0 {
-						log("The %d can be eliminated from cells %s since it can only be in symmetric cell group %s\n", value, excludable.LocationString(), subset.LocationString())
-						excludable.EliminateMove(value)
+						name := subsetName("naked", size)
+						s.eliminateStep(name, excludable, value, "The %d can be eliminated from cells %s since it can only be in %s %s", value, excludable.LocationString(), s.tr(name), subset.LocationString())
 						moves++
 					}
 				}
@@ -415,47 +1174,78 @@ func (s *Sudoku) Solve() error {
 		}
 	}
 
-	if len(s.Cells().UnsetOnly()) == 0 {
-		fmt.Println("Solved")
-		s.PrintBoard()
-		return nil
-	}
+	return moves
+}
 
-	if moves > 0 {
-		s.PrintBoard()
-		s.PrintMoves()
-		return s.Solve()
+// Hidden subsets larger than four cells are the complement of a smaller naked
+// subset in the same group, so there is no need to look for them.
+func (s *Sudoku) hiddenSubsets() int {
+	moves := 0
+
+	for _, group := range s.scanGroups() {
+		if s.unchanged("hidden subset", group) {
+			continue
+		}
+		group = group.UnsetOnly()
+		remaining := group.RemainingMoves()
+
+		for values := remaining; values != empty; values = (values - 1) & remaining {
+			size := values.Count()
+			if size < 2 || size > 4 || size >= len(group) {
+				continue
+			}
+
+			subset := make(Cells, 0, size)
+			for _, cell := range group {
+				if cell.moves&values != empty {
+					subset = append(subset, cell)
+				}
+			}
+			if len(subset) != size {
+				continue
+			}
+			// With no other candidates the cells are a naked subset, and there
+			// is nothing to eliminate
+			candidates := subset.RemainingMoves()
+			if candidates.Equal(values) {
+				continue
+			}
+
+			others := candidates &^ values
+			for _, value := range others.Slice() {
+				excludable := subset.FindMove(value)
+				if len(excludable) > 0 {
+					name := subsetName("hidden", size)
+					s.eliminateStep(name, excludable, value, "The %d can be eliminated from cells %s since %v can only be in %s %s", value, excludable.LocationString(), values.Slice(), s.tr(name), subset.LocationString())
+					moves++
+				}
+			}
+		}
 	}
 
-	//for _, cell := range s.Cells().UnsetOnly() {
-	//	for _, value := range cell.Moves() {
-	//		row := cell.row
-	//		col := cell.col
-	//		log("Guessing number %d in row %d column %d\n", value, row+1, col+1)
-	//
-	//		clone := s.Clone()
-	//		if err := clone.PlayMove(row, col, value); err != nil {
-	//			return err
-	//		}
-	//		if err := clone.Solve(); err != nil {
-	//			log("Bad guess: %d in row %d column %d\n", value, row+1, col+1)
-	//			continue
-	//		}
-	//		return nil
-	//	}
-	//}
+	return moves
+}
 
-	return errors.New("No solution found")
+func subsetName(kind string, size int) string {
+	switch size {
+	case 2:
+		return kind + " pair"
+	case 3:
+		return kind + " triple"
+	case 4:
+		return kind + " quad"
+	}
+	return "symmetric cell group"
 }
 
 func uniqueSquares(values []int) []int {
-	squaresPresent := [3]bool{}
+	squaresPresent := [BoxSize]bool{}
 	for _, value := range values {
-		squaresPresent[value / 3] = true
+		squaresPresent[value/BoxSize] = true
 	}
 
 	squares := make([]int, 0)
-	for square := 0; square < 3; square++ {
+	for square := 0; square < BoxSize; square++ {
 		if squaresPresent[square] {
 			squares = append(squares, square)
 		}
@@ -463,7 +1253,3 @@ func uniqueSquares(values []int) []int {
 
 	return squares
 }
-
-func log(format string, args ...interface{}) {
-	fmt.Printf(format, args...)
-}
@@ -0,0 +1,873 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loadPuzzle reads one of the puzzles in the puzzles directory, without
+// printing anything.
+func loadPuzzle(t *testing.T, name string) *Sudoku {
+	t.Helper()
+	puzzles, err := FileSource{Path: filepath.Join("..", "puzzles", name)}.Puzzles()
+	if err != nil {
+		t.Fatalf("Loading %s: %v", name, err)
+	}
+	s := puzzles[0]
+	s.SetOutput(io.Discard)
+	return s
+}
+
+// loadString parses a puzzle from text, without printing anything.
+func loadString(t *testing.T, text string, options ...ParseOption) *Sudoku {
+	t.Helper()
+	puzzles, err := StringSource{Text: text, Options: options}.Puzzles()
+	if err != nil {
+		t.Fatalf("Loading %q: %v", text, err)
+	}
+	s := puzzles[0]
+	s.SetOutput(io.Discard)
+	return s
+}
+
+// singleLine pads the given rows of a single-line puzzle with blank cells.
+func singleLine(cells string) string {
+	return cells + strings.Repeat(".", cellCount-len(cells))
+}
+
+// Row 1 needs both an 8 and a 9 in columns 8 and 9, but the 9s further down
+// those columns leave only the 8 for either cell.
+var contradiction = singleLine("1234567.." + "........." + "........." +
+	".......9." + "........." + "........." + "........9")
+
+func TestSolveDetailed(t *testing.T) {
+	tests := []struct {
+		name   string
+		puzzle *Sudoku
+		result SolveResult
+		err    error
+	}{
+		{"solved", loadPuzzle(t, "medium.txt"), Solved, nil},
+		{"requires guessing", loadPuzzle(t, "expert3.txt"), RequiresGuessing, ErrNoSolution},
+		{"contradiction", loadString(t, contradiction), Contradiction, nil},
+		{"multiple solutions", loadPuzzle(t, "blank.txt"), MultipleSolutions, ErrMultipleSolutions},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.puzzle.SolveDetailed()
+			if result != test.result {
+				t.Errorf("Got %v (%v), want %v", result, err, test.result)
+			}
+			if test.err != nil && !errors.Is(err, test.err) {
+				t.Errorf("Got error %v, want %v", err, test.err)
+			}
+			if result == Solved && err != nil {
+				t.Errorf("Solved with error %v", err)
+			}
+			if result != Solved && err == nil {
+				t.Errorf("%v without error", result)
+			}
+		})
+	}
+}
+
+func TestSolveWrapsSolveDetailed(t *testing.T) {
+	if err := loadPuzzle(t, "medium.txt").Solve(); err != nil {
+		t.Errorf("Solve returned %v", err)
+	}
+	if err := loadPuzzle(t, "blank.txt").Solve(); err != ErrMultipleSolutions {
+		t.Errorf("Solve on a blank board returned %v", err)
+	}
+}
+
+// blankSudoku returns an empty board that prints nothing, for tests that set
+// up candidates by hand.
+func blankSudoku(t *testing.T) *Sudoku {
+	t.Helper()
+	s, err := NewSudoku([Size][Size]int{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetOutput(io.Discard)
+	return s
+}
+
+func TestPointingPairs(t *testing.T) {
+	s := blankSudoku(t)
+	// The 1 of the top left square can only go in its top row
+	s.Square(0, 0).Excluding(s.Row(0)).EliminateMove(1)
+
+	if moves := s.pointingPairs(); moves != 1 {
+		t.Fatalf("Got %d moves, want 1", moves)
+	}
+	for col := 0; col < Size; col++ {
+		if want := col < BoxSize; s.Cell(0, col).CanPlay(1) != want {
+			t.Errorf("Row 1 column %d can play 1: %v, want %v", col+1, !want, want)
+		}
+	}
+	if !s.Cell(1, 5).CanPlay(1) {
+		t.Error("1 eliminated outside the top row")
+	}
+	if got, want := s.steps[0].Message, "In the top left square, the number 1 only fits in the top row"; got != want {
+		t.Errorf("Got message %q, want %q", got, want)
+	}
+	if moves := s.claiming(); moves != 0 {
+		t.Errorf("Claiming made %d moves on a pointing pair", moves)
+	}
+}
+
+func TestClaiming(t *testing.T) {
+	s := blankSudoku(t)
+	// The 1 of the top row can only go in the top left square
+	s.Row(0).Excluding(s.Square(0, 0)).EliminateMove(1)
+
+	if moves := s.claiming(); moves != 1 {
+		t.Fatalf("Got %d moves, want 1", moves)
+	}
+	for _, cell := range s.Square(0, 0) {
+		if want := cell.row == 0; cell.CanPlay(1) != want {
+			t.Errorf("Row %d column %d can play 1: %v, want %v", cell.row+1, cell.col+1, !want, want)
+		}
+	}
+	if !s.Cell(1, 5).CanPlay(1) {
+		t.Error("1 eliminated outside the top left square")
+	}
+	if got, want := s.steps[0].Message, "The 1 in the top left square must be in the top row"; got != want {
+		t.Errorf("Got message %q, want %q", got, want)
+	}
+}
+
+// gridOf parses a single-line puzzle into a grid, for the constructors that
+// take one.
+func gridOf(line string) [Size][Size]int {
+	grid := [Size][Size]int{}
+	for i := 0; i < cellCount; i++ {
+		if c := line[i]; c >= '1' && c <= '9' {
+			grid[i/Size][i%Size] = int(c - '0')
+		}
+	}
+	return grid
+}
+
+func TestDiagnosticReportsEveryDeadCell(t *testing.T) {
+	// Row 1 lacks only a 9 and row 9 only a 1, but column 9 already has both
+	s, rejected := NewSudokuLenient(gridOf("12345678." + "........." + "........." +
+		"........." + "........1" + "........9" + "........." + "........." + "23456789."))
+	if len(rejected) != 0 {
+		t.Fatalf("Rejected givens %v", rejected)
+	}
+	s.SetOutput(io.Discard)
+	s.SetDiagnostic(true)
+
+	var dead *DeadCellsError
+	if err := s.Solve(); !errors.As(err, &dead) {
+		t.Fatalf("Got error %v, want a DeadCellsError", err)
+	}
+	for _, cell := range []*Cell{s.Cell(0, 8), s.Cell(8, 8)} {
+		if !dead.Cells.Contains(cell) {
+			t.Errorf("Dead cell (%d,%d) not reported in %s", cell.row+1, cell.col+1, dead.Cells.LocationString())
+		}
+	}
+}
+
+func TestDiagnosticContinuesPastContradiction(t *testing.T) {
+	// Two copies of the contradiction puzzle's trap, in rows 1 and 9
+	puzzle := "1234567.." + "........." + "........." + ".......9." +
+		"........." + "........." + "........9" + "........." + "2345671.."
+
+	s := loadString(t, puzzle)
+	if _, err := s.SolveDetailed(); err == nil || errors.As(err, new(*DeadCellsError)) {
+		t.Errorf("Normal mode got %v, want the first contradiction only", err)
+	}
+
+	s = loadString(t, puzzle)
+	s.SetDiagnostic(true)
+	var dead *DeadCellsError
+	if _, err := s.SolveDetailed(); !errors.As(err, &dead) {
+		t.Fatalf("Got error %v, want a DeadCellsError", err)
+	}
+	rows := map[int]bool{}
+	for _, cell := range dead.Cells {
+		rows[cell.row] = true
+	}
+	if !rows[0] || !rows[Size-1] {
+		t.Errorf("Dead cells %s miss row 1 or row 9", dead.Cells.LocationString())
+	}
+}
+
+func TestNakedQuad(t *testing.T) {
+	s := blankSudoku(t)
+	row := s.Row(0)
+	for i, values := range [][]int{{1, 2}, {2, 3}, {3, 4}, {1, 4}} {
+		row[i].moves = movesOf(values...)
+	}
+
+	if moves := s.nakedSubsets(); moves != 4 {
+		t.Errorf("Got %d moves, want 4", moves)
+	}
+	for _, step := range s.steps {
+		if step.Technique != "naked quad" {
+			t.Errorf("Got technique %q, want naked quad", step.Technique)
+		}
+	}
+	for _, cell := range row[4:] {
+		if cell.moves != movesOf(5, 6, 7, 8, 9) {
+			t.Errorf("Row 1 column %d has candidates %v", cell.col+1, cell.Moves())
+		}
+	}
+	if want := "The 1 can be eliminated from cells (1,5), (1,6), (1,7), (1,8), (1,9) since it can only be in naked quad (1,1), (1,2), (1,3), (1,4)"; s.steps[0].Message != want {
+		t.Errorf("Got message %q, want %q", s.steps[0].Message, want)
+	}
+}
+
+func TestHiddenQuad(t *testing.T) {
+	s := blankSudoku(t)
+	row := s.Row(0)
+	row[4:].EliminateMoves(movesOf(1, 2, 3, 4))
+
+	if moves := s.hiddenSubsets(); moves != 5 {
+		t.Errorf("Got %d moves, want 5", moves)
+	}
+	for _, step := range s.steps {
+		if step.Technique != "hidden quad" {
+			t.Errorf("Got technique %q, want hidden quad", step.Technique)
+		}
+	}
+	for _, cell := range row[:4] {
+		if cell.moves != movesOf(1, 2, 3, 4) {
+			t.Errorf("Row 1 column %d has candidates %v", cell.col+1, cell.Moves())
+		}
+	}
+}
+
+func TestGroupsOrder(t *testing.T) {
+	s := blankSudoku(t)
+	groups := s.Groups()
+	if len(groups) != 3*Size {
+		t.Fatalf("Got %d groups, want %d", len(groups), 3*Size)
+	}
+
+	// Where the j-th cell of each kind of group i should be
+	kinds := []struct {
+		name string
+		cell func(i, j int) (int, int)
+	}{
+		{"row", func(i, j int) (int, int) { return i, j }},
+		{"column", func(i, j int) (int, int) { return j, i }},
+		{"square", func(i, j int) (int, int) {
+			return i/BoxSize*BoxSize + j/BoxSize, i%BoxSize*BoxSize + j%BoxSize
+		}},
+	}
+
+	for k, kind := range kinds {
+		for i := 0; i < Size; i++ {
+			group := groups[k*Size+i]
+			if len(group) != Size {
+				t.Fatalf("%s %d has %d cells", kind.name, i+1, len(group))
+			}
+			for j, cell := range group {
+				if row, col := kind.cell(i, j); cell.row != row || cell.col != col {
+					t.Errorf("Cell %d of %s %d is (%d,%d), want (%d,%d)", j+1, kind.name, i+1, cell.row+1, cell.col+1, row+1, col+1)
+				}
+			}
+		}
+	}
+}
+
+func TestSquareByIndex(t *testing.T) {
+	s := blankSudoku(t)
+	center := s.SquareByIndex(4)
+	if len(center) != Size {
+		t.Fatalf("Got %d cells, want %d", len(center), Size)
+	}
+	for _, cell := range center {
+		if cell.row < 3 || cell.row > 5 || cell.col < 3 || cell.col > 5 {
+			t.Errorf("Cell (%d,%d) is outside the center square", cell.row+1, cell.col+1)
+		}
+	}
+
+	for _, i := range []int{-1, Size} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("SquareByIndex(%d) did not panic", i)
+				}
+			}()
+			s.SquareByIndex(i)
+		}()
+	}
+}
+
+func TestMovesLayoutMatchesWriteMoves(t *testing.T) {
+	s := loadPuzzle(t, "hard1.txt")
+	layout := s.MovesLayout()
+
+	var out strings.Builder
+	s.WriteMoves(&out)
+	// A leading blank line, then three lines per row with a separator line
+	// between rows
+	lines := strings.Split(out.String(), "\n")[1:]
+
+	for row := 0; row < Size; row++ {
+		for moveRow := 0; moveRow < BoxSize; moveRow++ {
+			line := lines[row*(BoxSize+1)+moveRow]
+			for col := 0; col < Size; col++ {
+				for i := 0; i < BoxSize; i++ {
+					value := moveRow*BoxSize + i + 1
+					shown := line[col*(BoxSize+1)+i] == byte('0'+value)
+					if shown != layout[row][col][value-1] {
+						t.Errorf("Cell (%d,%d) shows %d: %v, layout has %v", row+1, col+1, value, shown, layout[row][col][value-1])
+					}
+					if shown != s.Cell(row, col).CanPlay(value) {
+						t.Errorf("Cell (%d,%d) shows %d: %v, but its candidates are %v", row+1, col+1, value, shown, s.Cell(row, col).Moves())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEmptyBoardHasMultipleSolutions(t *testing.T) {
+	s := loadPuzzle(t, "blank.txt")
+	result, err := s.SolveDetailed()
+	if result != MultipleSolutions || err != ErrMultipleSolutions {
+		t.Errorf("Got %v, %v; want %v, %v", result, err, MultipleSolutions, ErrMultipleSolutions)
+	}
+	if unset := len(s.Cells().UnsetOnly()); unset != cellCount {
+		t.Errorf("Filled %d cells of an empty board", cellCount-unset)
+	}
+}
+
+func TestNakedPair(t *testing.T) {
+	s := blankSudoku(t)
+	row := s.Row(0)
+	row[0].moves = movesOf(3, 7)
+	row[5].moves = movesOf(3, 7)
+
+	if moves := s.nakedSubsets(); moves != 2 {
+		t.Errorf("Got %d moves, want 2", moves)
+	}
+	for _, cell := range row.Excluding(Cells{row[0], row[5]}) {
+		if cell.CanPlay(3) || cell.CanPlay(7) {
+			t.Errorf("Row 1 column %d keeps candidates %v", cell.col+1, cell.Moves())
+		}
+	}
+	if s.steps[0].Technique != "naked pair" {
+		t.Errorf("Got technique %q, want naked pair", s.steps[0].Technique)
+	}
+}
+
+func TestRecomputeCandidates(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	want := s.Clone()
+
+	s.Cell(0, 1).moves = full
+	s.Cell(1, 2).moves = empty
+	s.Cell(0, 0).moves = movesOf(3)
+	s.RecomputeCandidates()
+
+	for _, cell := range s.Cells() {
+		if got, want := cell.moves, want.Cell(cell.row, cell.col).moves; got != want {
+			t.Errorf("(%d,%d) has candidates %v, want %v", cell.row+1, cell.col+1, got.Slice(), want.Slice())
+		}
+	}
+}
+
+func TestGroupNames(t *testing.T) {
+	s := blankSudoku(t)
+	if got := s.SquareName(0, 0); got != "top left" {
+		t.Errorf("Square (0,0) is %q, want top left", got)
+	}
+	if got := s.SquareName(2, 1); got != "bottom center" {
+		t.Errorf("Square (2,1) is %q, want bottom center", got)
+	}
+	if got := s.RowName(5); got != "bottom" {
+		t.Errorf("Row 6 is %q, want bottom", got)
+	}
+	if got := s.ColName(3); got != "left" {
+		t.Errorf("Column 4 is %q, want left", got)
+	}
+}
+
+func TestWarnsBelowSeventeenClues(t *testing.T) {
+	if warnings := loadString(t, singleLine("123456789"+"456789123")).Warnings(); len(warnings) != 0 {
+		t.Errorf("18 clues gave warnings %v", warnings)
+	}
+
+	var out strings.Builder
+	s, err := NewSudokuFromString(singleLine("123456789"+"4567891"), Output(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings := s.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "16 clues") {
+		t.Fatalf("Got warnings %v for 16 clues", warnings)
+	}
+	if !strings.Contains(out.String(), warnings[0]) {
+		t.Errorf("Warning not printed on load:\n%s", out.String())
+	}
+}
+
+func TestBoardConstants(t *testing.T) {
+	if BoxSize*BoxSize != Size || cellCount != Size*Size {
+		t.Errorf("Size %d, BoxSize %d and cellCount %d disagree", Size, BoxSize, cellCount)
+	}
+	all := full
+	if got := all.Count(); got != Size {
+		t.Errorf("Full mask has %d values", got)
+	}
+}
+
+// Solutions of the sample puzzles, as printed before the board size was named
+var sampleSolutions = map[string]string{
+	"medium.txt":  "316578492529134768487629531263415987974863125851792643138947256692351874745286319",
+	"hard1.txt":   "524973681978261453136584792617852349893146527245397816769428135382615974451739268",
+	"hard2.txt":   "392514867675938412148267935853426179416795283927183546781359624239641758564872391",
+	"expert1.txt": "421378569789654231653291748372985614516423987948716325265849173134567892897132456",
+	"expert2.txt": "374256198521849736698137425946325817187694352253718649469571283835462971712983564",
+}
+
+func TestSampleSolutions(t *testing.T) {
+	for name, want := range sampleSolutions {
+		s := loadPuzzle(t, name)
+		if err := s.Solve(); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got := s.String(); got != want {
+			t.Errorf("%s solved as\n%s\nwant\n%s", name, got, want)
+		}
+	}
+}
+
+func TestNewSudokuLenient(t *testing.T) {
+	grid := loadPuzzle(t, "medium.txt").Grid()
+	// A second 3 in row 1 and a second 9 in column 1
+	grid[0][1] = 3
+	grid[8][0] = 9
+
+	s, rejected := NewSudokuLenient(grid)
+	if len(rejected) != 2 {
+		t.Fatalf("Got %d rejected givens, want 2: %v", len(rejected), rejected)
+	}
+	for i, want := range []RejectedGiven{{Row: 0, Col: 1, Value: 3}, {Row: 8, Col: 0, Value: 9}} {
+		if got := rejected[i]; got.Row != want.Row || got.Col != want.Col || got.Value != want.Value || got.Err == nil {
+			t.Errorf("Rejected %+v, want %+v", got, want)
+		}
+	}
+
+	grid[0][1], grid[8][0] = 0, 0
+	if s.Grid() != grid {
+		t.Errorf("The other givens were not all loaded:\n%v", s)
+	}
+	if s.Cell(0, 1).Given() || !s.Cell(0, 0).Given() {
+		t.Error("Givens marked wrongly")
+	}
+}
+
+func TestLoadString(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	next := loadPuzzle(t, "hard1.txt")
+	if err := s.LoadString(next.String()); err != nil {
+		t.Fatal(err)
+	}
+	if s.Grid() != next.Grid() || len(s.Steps()) != 0 {
+		t.Errorf("Loaded\n%v\nwith %d steps, want\n%v", s, len(s.Steps()), next)
+	}
+	if err := s.Solve(); err != nil {
+		t.Fatalf("Solving the second puzzle: %v", err)
+	}
+	if want := solutionOf(t, next); s.Grid() != want {
+		t.Errorf("Solved the second puzzle as\n%v", s)
+	}
+}
+
+func TestLoadStringInvalid(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	before := s.Grid()
+	for _, text := range []string{"123", "11" + strings.Repeat(".", cellCount-2)} {
+		if err := s.LoadString(text, SingleLine()); err == nil {
+			t.Errorf("Loaded %q", text)
+		}
+		if s.Grid() != before {
+			t.Errorf("Failed load of %q changed the board", text)
+		}
+	}
+}
+
+func TestSolveCompleteBoard(t *testing.T) {
+	s, err := NewSudoku(gridOf(sampleSolutions["medium.txt"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	s.SetOutput(&out)
+
+	for i := 0; i < 2; i++ {
+		out.Reset()
+		if err := s.Solve(); err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Steps()) != 0 || s.rounds != 0 {
+			t.Errorf("Solving a complete board took %d steps in %d rounds", len(s.Steps()), s.rounds)
+		}
+		if !strings.HasPrefix(out.String(), "Solved\n") {
+			t.Errorf("Got output\n%s", out.String())
+		}
+	}
+}
+
+func TestSolveTwice(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	steps := len(s.Steps())
+	if err := s.Solve(); err != nil || len(s.Steps()) != steps {
+		t.Errorf("Second solve returned %v and added %d steps", err, len(s.Steps())-steps)
+	}
+}
+
+func TestConstraintMask(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	// Row 1 has 3 6 5 8 4, column 2 has 2 8 5 3 and the square adds 7
+	if got, want := s.ConstraintMask(0, 1), movesOf(2, 3, 4, 5, 6, 7, 8); got != want {
+		t.Errorf("Got %v, want %v", got.Slice(), want.Slice())
+	}
+	if got := s.ConstraintMask(0, 1); full&^got != s.Cell(0, 1).moves {
+		t.Errorf("Candidates %v do not match the mask", s.Cell(0, 1).Moves())
+	}
+	if got := blankSudoku(t).ConstraintMask(4, 4); got != empty {
+		t.Errorf("Blank board has %v placed", got.Slice())
+	}
+}
+
+func TestStuckError(t *testing.T) {
+	s := loadPuzzle(t, "expert3.txt")
+	_, err := s.SolveDetailed()
+
+	var stuck *StuckError
+	if !errors.As(err, &stuck) {
+		t.Fatalf("Got error %v, want a *StuckError", err)
+	}
+	if left := len(s.Cells().UnsetOnly()); stuck.Result != RequiresGuessing || stuck.Remaining != left || left == 0 {
+		t.Errorf("Got %+v with %d cells left", stuck, left)
+	}
+	if want := fmt.Sprintf("Stuck with %d cells left", stuck.Remaining); !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Got message %q", err)
+	}
+	if !errors.Is(err, ErrNoSolution) {
+		t.Error("StuckError does not match ErrNoSolution")
+	}
+}
+
+const mediumHeatmap = `
+· 2 ·|· 2 ·|· 2 3
+· · 3|6 3 4|3 2 4
+1 · ·|3 2 3|2 · ·
+-----+-----+-----
+4 3 ·|2 · 4|2 · 3
+· 3 3|· · ·|2 3 ·
+4 · 4|2 · 3|· 3 3
+-----+-----+-----
+· · 3|3 3 3|· · 3
+3 2 3|3 3 3|3 · ·
+3 3 ·|· 3 ·|· 2 2
+
+`
+
+func TestWriteHeatmap(t *testing.T) {
+	var out strings.Builder
+	loadPuzzle(t, "medium.txt").WriteHeatmap(&out)
+	if out.String() != mediumHeatmap {
+		t.Errorf("Got%s\nwant%s", out.String(), mediumHeatmap)
+	}
+}
+
+func TestGroupsOf(t *testing.T) {
+	s := blankSudoku(t)
+	groups := s.GroupsOf(4, 7)
+	want := []Cells{s.Row(4), s.Col(7), s.Square(1, 2)}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("Got %d groups %v", len(groups), groups)
+	}
+	for _, group := range groups {
+		if !group.Contains(s.Cell(4, 7)) {
+			t.Errorf("Group %s does not contain the cell", group.LocationString())
+		}
+	}
+}
+
+func TestMostConstrainedGroup(t *testing.T) {
+	// Row 6 is missing only its last cell
+	s := loadString(t, singleLine("........."+"........."+"........."+"........."+"........."+"12345678."))
+	group, count := s.MostConstrainedGroup()
+	if count != 1 || !reflect.DeepEqual(group, s.Row(5)) {
+		t.Errorf("Got %s with %d empty, want row 6 with 1", group.LocationString(), count)
+	}
+
+	s = loadPuzzle(t, "medium.txt")
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if group, count := s.MostConstrainedGroup(); group != nil || count != 0 {
+		t.Errorf("Solved board returned %s with %d empty", group.LocationString(), count)
+	}
+}
+
+func TestSees(t *testing.T) {
+	s := blankSudoku(t)
+	for _, test := range []struct {
+		name           string
+		r1, c1, r2, c2 int
+		want           bool
+	}{
+		{"same row", 2, 0, 2, 8, true},
+		{"same column", 0, 5, 7, 5, true},
+		{"same square", 3, 3, 5, 4, true},
+		{"unrelated", 0, 0, 4, 4, false},
+		{"same band only", 0, 0, 1, 4, false},
+		{"same cell", 4, 4, 4, 4, false},
+	} {
+		if got := s.Sees(test.r1, test.c1, test.r2, test.c2); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestEach(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, cells := 0, 0
+	s.Each(func(row, col int, value int) {
+		if value != s.Cell(row, col).value {
+			t.Errorf("(%d,%d) visited with %d", row+1, col+1, value)
+		}
+		sum += value
+		cells++
+	})
+	// Each row of a solution holds 1 to 9
+	if cells != cellCount || sum != Size*45 {
+		t.Errorf("Visited %d cells summing to %d", cells, sum)
+	}
+}
+
+func TestClueDistribution(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	bands, stacks := s.ClueDistribution()
+	if want := [BoxSize]int{11, 11, 10}; bands != want {
+		t.Errorf("Bands have %v clues, want %v", bands, want)
+	}
+	if want := [BoxSize]int{12, 9, 11}; stacks != want {
+		t.Errorf("Stacks have %v clues, want %v", stacks, want)
+	}
+
+	// Placed values are not clues
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := s.ClueDistribution(); after != bands {
+		t.Errorf("Solving changed the bands to %v", after)
+	}
+}
+
+func guesses(s *Sudoku) int {
+	count := 0
+	for _, step := range s.Steps() {
+		if step.Technique == "guess" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestGuessingIsLastResort(t *testing.T) {
+	s := loadPuzzle(t, "expert1.txt")
+	if nodes, _ := s.SearchNodes(); nodes != 0 {
+		t.Fatalf("expert1.txt needs %d search nodes", nodes)
+	}
+	s.SetGuessing(true)
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if got := guesses(s); got != 0 {
+		t.Errorf("Guessed %d times on a puzzle logic can solve", got)
+	}
+}
+
+func TestGuessingFinishesStuckPuzzle(t *testing.T) {
+	s := loadPuzzle(t, "expert3.txt")
+	solutions, err := s.Solutions(2)
+	if err != nil || len(solutions) != 1 {
+		t.Fatalf("Got %d solutions, %v", len(solutions), err)
+	}
+	s.SetGuessing(true)
+
+	if result, err := s.SolveDetailed(); result != Solved || err != nil {
+		t.Fatalf("Got %v, %v", result, err)
+	}
+	if s.Grid() != solutions[0].Grid() {
+		t.Errorf("Guessed wrongly:\n%v", s)
+	}
+	if got := guesses(s); got == 0 {
+		t.Error("Solved without a guess")
+	}
+
+	// Replay the steps to check that no technique applied before each guess
+	replay := loadPuzzle(t, "expert3.txt")
+	for _, step := range s.Steps() {
+		if step.Technique == "guess" {
+			if name, err := replay.NextTechnique(); err != ErrNoTechnique {
+				t.Fatalf("Guessed %s while %q applied", step.Describe(), name)
+			}
+		}
+		if step.Action == Place {
+			replay.PlayMove(step.Cells[0].row, step.Cells[0].col, step.Value)
+			continue
+		}
+		for _, cell := range step.Cells {
+			replay.Cell(cell.row, cell.col).EliminateMove(step.Value)
+		}
+	}
+}
+
+func TestDigitCounts(t *testing.T) {
+	want := [Size + 1]int{49, 3, 3, 6, 2, 6, 4, 2, 4, 2}
+	if got := loadPuzzle(t, "medium.txt").DigitCounts(); got != want {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
+
+func TestNewSudokuFromClues(t *testing.T) {
+	s, err := NewSudokuFromClues([]Clue{{0, 0, 3}, {0, 2, 6}, {4, 8, 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [Size][Size]int{}
+	want[0][0], want[0][2], want[4][8] = 3, 6, 5
+	if s.Grid() != want {
+		t.Errorf("Got\n%v", s)
+	}
+	if !s.Cell(0, 2).Given() || s.Cell(0, 1).Given() {
+		t.Error("Clues not marked as givens")
+	}
+	if s.Cell(0, 1).moves.Contains(3) {
+		t.Error("Clue left as a candidate of its row")
+	}
+}
+
+func TestNewSudokuFromCluesConflict(t *testing.T) {
+	_, err := NewSudokuFromClues([]Clue{{0, 0, 3}, {1, 1, 4}, {2, 2, 3}})
+	if err == nil || !strings.HasPrefix(err.Error(), "Clue 2:") {
+		t.Errorf("Got error %v, want one for clue 2", err)
+	}
+}
+
+func TestPlacedCells(t *testing.T) {
+	s := loadPuzzle(t, "expert1.txt")
+	s.SolveLogicalOnly(TierSingles)
+
+	total := 0
+	for value := 1; value <= Size; value++ {
+		placed := s.PlacedCells(value)
+		for _, cell := range placed {
+			if cell.value != value {
+				t.Errorf("%d listed at (%d,%d), which holds %d", value, cell.row+1, cell.col+1, cell.value)
+			}
+		}
+		total += len(placed)
+	}
+	if want := cellCount - len(s.Cells().UnsetOnly()); total != want {
+		t.Errorf("Got %d placed cells, want %d", total, want)
+	}
+
+	for _, value := range []int{0, -1, Size + 1} {
+		if got := s.PlacedCells(value); got == nil || len(got) != 0 {
+			t.Errorf("Value %d matched %v", value, got)
+		}
+	}
+}
+
+const defaultBoard = `
+3 1 6|5 7 8|4 9 2
+5 2 9|1 3 4|7 6 8
+4 8 7|6 2 9|5 3 1
+-----+-----+-----
+2 6 3|4 1 5|9 8 7
+9 7 4|8 6 3|1 2 5
+8 5 1|7 9 2|6 4 3
+-----+-----+-----
+1 3 8|9 4 7|2 5 6
+6 9 2|3 5 1|8 7 4
+7 4 5|2 8 6|3 1 9
+
+`
+
+const boxDrawingBoard = `
+3 1 6│5 7 8│4 9 2
+5 2 9│1 3 4│7 6 8
+4 8 7│6 2 9│5 3 1
+─────┼─────┼─────
+2 6 3│4 1 5│9 8 7
+9 7 4│8 6 3│1 2 5
+8 5 1│7 9 2│6 4 3
+─────┼─────┼─────
+1 3 8│9 4 7│2 5 6
+6 9 2│3 5 1│8 7 4
+7 4 5│2 8 6│3 1 9
+
+`
+
+const unseparatedBoard = `
+3 1 6 5 7 8 4 9 2
+5 2 9 1 3 4 7 6 8
+4 8 7 6 2 9 5 3 1
+2 6 3 4 1 5 9 8 7
+9 7 4 8 6 3 1 2 5
+8 5 1 7 9 2 6 4 3
+1 3 8 9 4 7 2 5 6
+6 9 2 3 5 1 8 7 4
+7 4 5 2 8 6 3 1 9
+
+`
+
+func TestWriteBoardStyled(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range map[string]struct {
+		style BoardStyle
+		want  string
+	}{
+		"default":      {DefaultBoardStyle, defaultBoard},
+		"box drawing":  {BoardStyle{Horizontal: '─', Vertical: '│', Cross: '┼'}, boxDrawingBoard},
+		"no separator": {BoardStyle{}, unseparatedBoard},
+	} {
+		var out strings.Builder
+		s.WriteBoardStyled(&out, test.style)
+		if out.String() != test.want {
+			t.Errorf("%s style: got\n%s\nwant\n%s", name, out.String(), test.want)
+		}
+	}
+
+	var out strings.Builder
+	s.WriteBoard(&out)
+	if out.String() != defaultBoard {
+		t.Errorf("WriteBoard drew\n%s", out.String())
+	}
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+)
+
+type SVGOptions struct {
+	// Width and height of each cell in pixels. Zero uses 40.
+	CellSize int
+
+	// Draw the candidates of unset cells as small digits
+	PencilMarks bool
+}
+
+// WriteSVG writes the board as a standalone SVG image. Givens are drawn in
+// bold black, and other filled values in blue.
+func (s *Sudoku) WriteSVG(w io.Writer, opts SVGOptions) {
+	cellSize := opts.CellSize
+	if cellSize == 0 {
+		cellSize = 40
+	}
+	size := cellSize * Size
+
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", size, size, size, size)
+	fmt.Fprintf(w, "<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", size, size)
+
+	for i := 0; i <= Size; i++ {
+		width := 1
+		if i%BoxSize == 0 {
+			width = 3
+		}
+		at := i * cellSize
+		fmt.Fprintf(w, "<line x1=\"%d\" y1=\"0\" x2=\"%d\" y2=\"%d\" stroke=\"black\" stroke-width=\"%d\"/>\n", at, at, size, width)
+		fmt.Fprintf(w, "<line x1=\"0\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\" stroke-width=\"%d\"/>\n", at, size, at, width)
+	}
+
+	font := "font-family=\"sans-serif\" text-anchor=\"middle\" dominant-baseline=\"central\""
+	for _, cell := range s.Cells() {
+		left, top := cell.col*cellSize, cell.row*cellSize
+
+		if cell.value != 0 {
+			style := "fill=\"#2a5db0\""
+			if cell.given {
+				style = "fill=\"black\" font-weight=\"bold\""
+			}
+			fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" font-size=\"%d\" %s %s>%d</text>\n", left+cellSize/2, top+cellSize/2, cellSize*3/5, font, style, cell.value)
+			continue
+		}
+
+		if !opts.PencilMarks {
+			continue
+		}
+		// Candidates sit in a small grid within the cell, 1 at the top left
+		mark := cellSize / BoxSize
+		for _, value := range cell.Moves() {
+			x := left + (value-1)%BoxSize*mark + mark/2
+			y := top + (value-1)/BoxSize*mark + mark/2
+			fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" font-size=\"%d\" %s fill=\"gray\">%d</text>\n", x, y, mark*3/4, font, value)
+		}
+	}
+
+	fmt.Fprintln(w, "</svg>")
+}
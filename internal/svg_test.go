@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteSVG(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	filled := len(s.Cells()) - len(s.Cells().UnsetOnly())
+	candidates := 0
+	for _, cell := range s.Cells().UnsetOnly() {
+		candidates += cell.moves.Count()
+	}
+
+	for _, test := range []struct {
+		opts SVGOptions
+		want int
+	}{
+		{SVGOptions{}, filled},
+		{SVGOptions{PencilMarks: true, CellSize: 60}, filled + candidates},
+	} {
+		var out strings.Builder
+		s.WriteSVG(&out, test.opts)
+
+		if got := strings.Count(out.String(), "<text "); got != test.want {
+			t.Errorf("%+v: got %d text elements, want %d", test.opts, got, test.want)
+		}
+		if err := xml.Unmarshal([]byte(out.String()), new(struct{})); err != nil {
+			t.Errorf("%+v: invalid SVG: %v", test.opts, err)
+		}
+	}
+}
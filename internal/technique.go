@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Tier int
+
+const (
+	TierSingles Tier = iota
+	TierIntersections
+	TierSubsets
+	TierChains
+	TierUniqueness
+)
+
+func (t Tier) String() string {
+	switch t {
+	case TierSingles:
+		return "singles"
+	case TierIntersections:
+		return "intersections"
+	case TierSubsets:
+		return "subsets"
+	case TierChains:
+		return "chains"
+	case TierUniqueness:
+		return "uniqueness"
+	}
+	return fmt.Sprintf("Tier(%d)", int(t))
+}
+
+// ParseTier returns the tier with the given name, as printed by String.
+func ParseTier(name string) (Tier, error) {
+	for t := TierSingles; t <= TierUniqueness; t++ {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown tier %q", name)
+}
+
+type technique struct {
+	name  string
+	tier  Tier
+	apply func(s *Sudoku) (int, error)
+}
+
+// Techniques in the order Solve applies them during each round.
+var techniques = []technique{
+	{"naked single", TierSingles, (*Sudoku).nakedSingles},
+	{"hidden single", TierSingles, (*Sudoku).hiddenSingles},
+	{"pointing pair", TierIntersections, infallible((*Sudoku).pointingPairs)},
+	{"claiming", TierIntersections, infallible((*Sudoku).claiming)},
+	{"naked subset", TierSubsets, infallible((*Sudoku).nakedSubsets)},
+	{"hidden subset", TierSubsets, infallible((*Sudoku).hiddenSubsets)},
+	{"XY-chain", TierChains, infallible((*Sudoku).xyChains)},
+	{"BUG+1", TierUniqueness, (*Sudoku).bugPlusOne},
+	{"unique rectangle", TierUniqueness, infallible((*Sudoku).uniqueRectangles)},
+}
+
+func infallible(apply func(s *Sudoku) int) func(s *Sudoku) (int, error) {
+	return func(s *Sudoku) (int, error) {
+		return apply(s), nil
+	}
+}
+
+// SolveLogicalOnly solves with techniques up to the given tier and never
+// searches. TierUniqueness assumes the puzzle has a unique solution.
+func (s *Sudoku) SolveLogicalOnly(max Tier) error {
+	return s.solveUpTo(max)
+}
+
+var ErrNoTechnique = errors.New("No technique makes progress")
+
+func (s *Sudoku) NextTechnique() (string, error) {
+	if len(s.Cells().UnsetOnly()) == 0 {
+		return "", errors.New("Puzzle is already solved")
+	}
+
+	for _, technique := range techniques {
+		if technique.tier > s.maxTier() && technique.tier > s.safeTier() {
+			continue
+		}
+		clone := s.quietClone()
+		changes, err := technique.apply(clone)
+		if err != nil {
+			return "", err
+		}
+		if changes > 0 {
+			return clone.steps[0].Technique, nil
+		}
+	}
+
+	return "", ErrNoTechnique
+}
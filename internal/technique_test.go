@@ -0,0 +1,40 @@
+package internal
+
+import "testing"
+
+func TestNextTechniqueHiddenSingle(t *testing.T) {
+	s := blankSudoku(t)
+	s.Square(1, 1).Excluding(Cells{s.Cell(4, 4)}).EliminateMove(7)
+
+	name, err := s.NextTechnique()
+	if name != "hidden single" || err != nil {
+		t.Errorf("Got %q, %v; want hidden single", name, err)
+	}
+	if s.Cell(4, 4).value != 0 || len(s.steps) != 0 {
+		t.Error("NextTechnique changed the puzzle")
+	}
+}
+
+func TestNextTechniqueEasiestFirst(t *testing.T) {
+	s := blankSudoku(t)
+	s.Square(1, 1).Excluding(Cells{s.Cell(4, 4)}).EliminateMove(7)
+	s.Cell(8, 8).moves = movesOf(2)
+
+	if name, _ := s.NextTechnique(); name != "naked single" {
+		t.Errorf("Got %q, want naked single", name)
+	}
+}
+
+func TestNextTechniqueStuck(t *testing.T) {
+	if _, err := blankSudoku(t).NextTechnique(); err != ErrNoTechnique {
+		t.Errorf("Got error %v on a blank board, want %v", err, ErrNoTechnique)
+	}
+
+	s := loadPuzzle(t, "medium.txt")
+	if err := s.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.NextTechnique(); err == nil {
+		t.Error("Solved puzzle has a next technique")
+	}
+}
@@ -0,0 +1,147 @@
+package internal
+
+import "fmt"
+
+func (s *Sudoku) Grid() [Size][Size]int {
+	grid := [Size][Size]int{}
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
+			grid[row][col] = s.board[row][col].value
+		}
+	}
+	return grid
+}
+
+// givenGrid is like Grid, but holds only the givens.
+func (s *Sudoku) givenGrid() [Size][Size]int {
+	grid := [Size][Size]int{}
+	for _, cell := range s.Cells() {
+		if cell.given {
+			grid[cell.row][cell.col] = cell.value
+		}
+	}
+	return grid
+}
+
+func (s *Sudoku) Transpose() *Sudoku {
+	return s.transform(func(row, col int) (int, int) {
+		return col, row
+	})
+}
+
+func (s *Sudoku) RotateCW() *Sudoku {
+	return s.transform(func(row, col int) (int, int) {
+		return col, Size - 1 - row
+	})
+}
+
+func (s *Sudoku) Reflect() *Sudoku {
+	return s.transform(func(row, col int) (int, int) {
+		return row, Size - 1 - col
+	})
+}
+
+func (s *Sudoku) transform(move func(row, col int) (int, int)) *Sudoku {
+	// The values are set directly rather than played, so that this cannot
+	// fail: symmetry transforms map rows, columns and squares onto each
+	// other, so a valid board stays valid, and an invalid one keeps the same
+	// conflicts.
+	t := newEmptySudoku()
+	for _, cell := range s.Cells() {
+		if cell.value != 0 {
+			newRow, newCol := move(cell.row, cell.col)
+			t.board[newRow][newCol].value = cell.value
+			t.board[newRow][newCol].given = true
+		}
+	}
+	t.RecomputeCandidates()
+	return t
+}
+
+func (s *Sudoku) Relabel(perm [Size]int) (*Sudoku, error) {
+	seen := empty
+	for _, value := range perm {
+		if value < 1 || value > Size {
+			return nil, fmt.Errorf("Permutation value %d out of range", value)
+		}
+		if !seen.Add(value) {
+			return nil, fmt.Errorf("Permutation contains %d more than once", value)
+		}
+	}
+
+	grid := s.Grid()
+	for row := 0; row < Size; row++ {
+		for col := 0; col < Size; col++ {
+			if value := grid[row][col]; value != 0 {
+				grid[row][col] = perm[value-1]
+			}
+		}
+	}
+
+	return NewSudoku(grid)
+}
+
+var symmetries = []struct {
+	name string
+	move func(row, col int) (int, int)
+}{
+	{"rotational", func(row, col int) (int, int) { return Size - 1 - row, Size - 1 - col }},
+	{"horizontal mirror", func(row, col int) (int, int) { return row, Size - 1 - col }},
+	{"vertical mirror", func(row, col int) (int, int) { return Size - 1 - row, col }},
+}
+
+func (s *Sudoku) CluesAreSymmetric() (bool, string) {
+	for _, symmetry := range symmetries {
+		symmetric := true
+		for _, cell := range s.Cells() {
+			row, col := symmetry.move(cell.row, cell.col)
+			if cell.given != s.board[row][col].given {
+				symmetric = false
+				break
+			}
+		}
+		if symmetric {
+			return true, symmetry.name
+		}
+	}
+	return false, ""
+}
+
+// Canonical returns a key shared by all puzzles that are equivalent under
+// rotation, reflection and relabeling of digits. Each of the eight rotations
+// and reflections is relabeled so digits are numbered in order of first
+// appearance, and the smallest resulting board, written as 81 characters with
+// '.' for blanks, is the key. Permutations of rows and columns within bands
+// and stacks are not considered.
+func (s *Sudoku) Canonical() string {
+	best := ""
+	board := s
+	for rotation := 0; rotation < 4; rotation++ {
+		for _, variant := range []*Sudoku{board, board.Reflect()} {
+			if key := variant.relabeledKey(); best == "" || key < best {
+				best = key
+			}
+		}
+		board = board.RotateCW()
+	}
+	return best
+}
+
+func (s *Sudoku) relabeledKey() string {
+	perm := [Size + 1]int{}
+	next := 1
+
+	key := make([]byte, 0, cellCount)
+	for _, cell := range s.Cells() {
+		if cell.value == 0 {
+			key = append(key, '.')
+			continue
+		}
+		if perm[cell.value] == 0 {
+			perm[cell.value] = next
+			next++
+		}
+		key = append(key, byte('0'+perm[cell.value]))
+	}
+	return string(key)
+}
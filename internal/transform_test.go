@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"io"
+	"testing"
+)
+
+func TestRotateCWFourTimes(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	rotated := s
+	for i := 0; i < 4; i++ {
+		rotated = rotated.RotateCW()
+	}
+	if rotated.Grid() != s.Grid() {
+		t.Errorf("Four rotations changed the board:\n%v", rotated)
+	}
+}
+
+func TestTransformsAreInvolutions(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	if got := s.Transpose().Transpose(); got.Grid() != s.Grid() {
+		t.Errorf("Transposing twice changed the board:\n%v", got)
+	}
+	if got := s.Reflect().Reflect(); got.Grid() != s.Grid() {
+		t.Errorf("Reflecting twice changed the board:\n%v", got)
+	}
+}
+
+func TestTransformsMoveCells(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	value := s.Cell(0, 2).value
+
+	if got := s.Transpose().Cell(2, 0).value; got != value {
+		t.Errorf("Transpose put %d at row 3 column 1, want %d", got, value)
+	}
+	if got := s.RotateCW().Cell(2, Size-1).value; got != value {
+		t.Errorf("RotateCW put %d at row 3 column 9, want %d", got, value)
+	}
+	if got := s.Reflect().Cell(0, Size-3).value; got != value {
+		t.Errorf("Reflect put %d at row 1 column 7, want %d", got, value)
+	}
+	if s.RotateCW().Grid() != s.Transpose().Reflect().Grid() {
+		t.Error("RotateCW differs from a transpose followed by a reflection")
+	}
+}
+
+func TestTransformsStaySolvable(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	for name, variant := range map[string]*Sudoku{
+		"transposed": s.Transpose(),
+		"rotated":    s.RotateCW(),
+		"reflected":  s.Reflect(),
+	} {
+		if !variant.IsValid() {
+			t.Errorf("The %s board has inconsistent candidates", name)
+		}
+		variant.SetOutput(io.Discard)
+		if err := variant.Solve(); err != nil {
+			t.Errorf("The %s board did not solve: %v", name, err)
+		}
+	}
+}
+
+func TestRelabelInverse(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	perm := [Size]int{3, 7, 1, 9, 2, 8, 4, 6, 5}
+	inverse := [Size]int{}
+	for i, value := range perm {
+		inverse[value-1] = i + 1
+	}
+
+	relabeled, err := s.Relabel(perm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !relabeled.IsValid() {
+		t.Error("The relabeled board is inconsistent")
+	}
+	if got, want := relabeled.Cell(0, 0).value, perm[s.Cell(0, 0).value-1]; got != want {
+		t.Errorf("Row 1 column 1 relabeled to %d, want %d", got, want)
+	}
+
+	restored, err := relabeled.Relabel(inverse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Grid() != s.Grid() {
+		t.Errorf("Relabeling by the inverse gave\n%v", restored)
+	}
+}
+
+func TestRelabelRejectsInvalidPermutations(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	for _, perm := range [][Size]int{
+		{1, 2, 3, 4, 5, 6, 7, 8, 8},
+		{0, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 10},
+	} {
+		if _, err := s.Relabel(perm); err == nil {
+			t.Errorf("Relabel accepted %v", perm)
+		}
+	}
+}
+
+func TestCluesAreSymmetric(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		second [2]int
+		want   string
+	}{
+		{"rotational", [2]int{8, 8}, "rotational"},
+		{"mirrored left to right", [2]int{0, 8}, "horizontal mirror"},
+		{"mirrored top to bottom", [2]int{8, 0}, "vertical mirror"},
+		{"asymmetric", [2]int{1, 2}, ""},
+	} {
+		var grid [Size][Size]int
+		grid[0][0] = 1
+		grid[test.second[0]][test.second[1]] = 2
+		s, err := NewSudoku(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		symmetric, name := s.CluesAreSymmetric()
+		if symmetric != (test.want != "") || name != test.want {
+			t.Errorf("%s: got %v, %q; want %q", test.name, symmetric, name, test.want)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	relabeled, err := s.Relabel([Size]int{3, 7, 1, 9, 2, 8, 4, 6, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := s.Canonical()
+
+	for name, variant := range map[string]*Sudoku{
+		"rotated":                 s.RotateCW(),
+		"transposed":              s.Transpose(),
+		"relabeled":               relabeled,
+		"reflected and relabeled": relabeled.Reflect().RotateCW(),
+	} {
+		if got := variant.Canonical(); got != key {
+			t.Errorf("The %s puzzle has key %s, want %s", name, got, key)
+		}
+	}
+
+	if len(key) != cellCount {
+		t.Errorf("Key %q is not %d characters", key, cellCount)
+	}
+	if other := loadPuzzle(t, "hard1.txt").Canonical(); other == key {
+		t.Error("Different puzzles share a key")
+	}
+}
+
+func TestTransformInconsistentBoard(t *testing.T) {
+	grid := [Size][Size]int{}
+	grid[0][0], grid[0][1] = 5, 5
+	s, _ := NewSudokuLenient(grid)
+	s.Cell(0, 1).value = 5
+
+	transposed := s.Transpose()
+	if transposed == nil || transposed.Cell(1, 0).value != 5 || transposed.IsValid() {
+		t.Errorf("Got\n%v", transposed)
+	}
+}
@@ -0,0 +1,117 @@
+package internal
+
+// Techniques in this file assume the puzzle has a unique solution, and can
+// make wrong deductions on puzzles that do not.
+
+// A bivalue universal grave is a position where every unsolved cell has two
+// candidates and every candidate appears twice in each group. Such a position
+// has either no solution or two, so when all unsolved cells but one are
+// bivalue, the extra cell must take the candidate that breaks the pattern:
+// the one without which the rest of the board is a grave.
+func (s *Sudoku) bugPlusOne() (int, error) {
+	unset := s.Cells().UnsetOnly()
+	if len(s.BiValueCells()) != len(unset)-1 {
+		return 0, nil
+	}
+
+	var extra *Cell
+	for _, cell := range unset {
+		if count := cell.moves.Count(); count == 3 {
+			extra = cell
+		} else if count != 2 {
+			return 0, nil
+		}
+	}
+	if extra == nil {
+		return 0, nil
+	}
+
+	for _, value := range extra.Moves() {
+		if s.isGrave(extra, value) {
+			err := s.placeStep("BUG+1", extra, value, "Every other unsolved cell has two candidates, so row %d column %d must be %d to avoid two solutions", extra.row+1, extra.col+1, value)
+			if err != nil {
+				return 0, err
+			}
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// isGrave reports whether each candidate appears either twice or not at all in
+// every group, leaving out value from the extra cell.
+func (s *Sudoku) isGrave(extra *Cell, value int) bool {
+	for _, group := range s.Groups() {
+		counts := [Size + 1]int{}
+		for _, cell := range group.UnsetOnly() {
+			for _, v := range cell.Moves() {
+				if cell != extra || v != value {
+					counts[v]++
+				}
+			}
+		}
+		for _, count := range counts {
+			if count != 0 && count != 2 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// A unique rectangle is four unsolved cells in two rows, two columns and two
+// squares that all hold candidates a and b. Were every corner left with just a
+// and b, the two could be swapped for a second solution. So when three
+// corners are the bivalue cell ab, the fourth corner cannot be a or b, and
+// must take one of its extra candidates (type 1).
+func (s *Sudoku) uniqueRectangles() int {
+	for r1 := 0; r1 < Size; r1++ {
+		for r2 := r1 + 1; r2 < Size; r2++ {
+			for c1 := 0; c1 < Size; c1++ {
+				for c2 := c1 + 1; c2 < Size; c2++ {
+					// Exactly two squares: a shared band or a shared stack, not both
+					if (r1/BoxSize == r2/BoxSize) == (c1/BoxSize == c2/BoxSize) {
+						continue
+					}
+					corners := Cells{&s.board[r1][c1], &s.board[r1][c2], &s.board[r2][c1], &s.board[r2][c2]}
+					if s.uniqueRectangleType1(corners) {
+						return 1
+					}
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func (s *Sudoku) uniqueRectangleType1(corners Cells) bool {
+	var pair Moves
+	var extra *Cell
+	for _, cell := range corners {
+		if cell.value != 0 {
+			return false
+		}
+		if cell.moves.Count() != 2 {
+			if extra != nil {
+				return false
+			}
+			extra = cell
+			continue
+		}
+		if pair == 0 {
+			pair = cell.moves
+		} else if !pair.Equal(cell.moves) {
+			return false
+		}
+	}
+	if extra == nil || pair == 0 || extra.moves&pair != pair {
+		return false
+	}
+
+	others := corners.Excluding(Cells{extra})
+	for _, value := range pair.Slice() {
+		s.eliminateStep("unique rectangle", Cells{extra}, value, "The %d can be eliminated from row %d column %d since cells %s would otherwise allow two solutions", value, extra.row+1, extra.col+1, others.LocationString())
+	}
+	return true
+}
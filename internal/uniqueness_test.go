@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"io"
+	"testing"
+)
+
+// bugPosition returns the solution of medium.txt with a rectangle of four
+// cells left blank, in rows 1 and 4 and columns 2 and 3. Their solution is
+//
+//	1 6
+//	6 3
+//
+// The candidates are set by hand so that the 6 and the 3 make a grave in
+// which the first cell also has its own value, 1.
+func bugPosition(t *testing.T) *Sudoku {
+	t.Helper()
+	grid := solutionOf(t, loadPuzzle(t, "medium.txt"))
+	for _, at := range [][2]int{{0, 1}, {0, 2}, {3, 1}, {3, 2}} {
+		grid[at[0]][at[1]] = 0
+	}
+	s, err := NewSudoku(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetOutput(io.Discard)
+
+	s.Cell(0, 1).moves = movesOf(1, 3, 6)
+	for _, cell := range (Cells{s.Cell(0, 2), s.Cell(3, 1), s.Cell(3, 2)}) {
+		cell.moves = movesOf(3, 6)
+	}
+	return s
+}
+
+func TestBugPlusOne(t *testing.T) {
+	s := bugPosition(t)
+	count, err := s.bugPlusOne()
+	if count != 1 || err != nil {
+		t.Fatalf("Got %d, %v; want one placement", count, err)
+	}
+	if got := s.Cell(0, 1).value; got != 1 {
+		t.Errorf("Placed %d in row 1 column 2, want 1", got)
+	}
+	if steps := s.Steps(); len(steps) != 1 || steps[0].Technique != "BUG+1" {
+		t.Errorf("Got steps %v", steps)
+	}
+}
+
+func TestBugPlusOneNeedsGrave(t *testing.T) {
+	// Every cell but one is still bivalue, but the 3 appears once in row 1
+	// and column 3, whichever candidate of the extra cell is left out
+	s := bugPosition(t)
+	s.Cell(0, 2).moves = movesOf(1, 6)
+
+	if count, err := s.bugPlusOne(); count != 0 || err != nil {
+		t.Errorf("Got %d, %v; want nothing placed", count, err)
+	}
+	if got := s.Cell(0, 1).value; got != 0 {
+		t.Errorf("Placed %d in row 1 column 2", got)
+	}
+}
+
+func TestUniqueRectangle(t *testing.T) {
+	// Three corners of the rectangle in rows 1 and 4, columns 2 and 3 are
+	// the bivalue cell 36, so the 3 and the 6 go from the fourth corner
+	s := bugPosition(t)
+
+	if got := s.uniqueRectangles(); got != 1 {
+		t.Fatalf("Found %d rectangles, want 1", got)
+	}
+	if got := s.Cell(0, 1).moves; got != movesOf(1) {
+		t.Errorf("Row 1 column 2 left with %v, want [1]", got.Slice())
+	}
+	for _, step := range s.Steps() {
+		if step.Technique != "unique rectangle" || step.Cells.LocationString() != "(1,2)" {
+			t.Errorf("Got step %s", step.Describe())
+		}
+	}
+	if got := len(s.Steps()); got != 2 {
+		t.Errorf("Got %d steps, want one for each of 3 and 6", got)
+	}
+}
+
+func TestUniqueRectangleNeedsThreeBivalueCorners(t *testing.T) {
+	s := bugPosition(t)
+	s.Cell(3, 2).moves = movesOf(3, 6, 9)
+	if got := s.uniqueRectangles(); got != 0 {
+		t.Errorf("Found %d rectangles with two extra corners", got)
+	}
+
+	// The corners of a rectangle within one square can be swapped by
+	// other cells of the square
+	s = blankSudoku(t)
+	for _, cell := range (Cells{s.Cell(0, 0), s.Cell(0, 1), s.Cell(1, 0)}) {
+		cell.moves = movesOf(3, 6)
+	}
+	if got := s.uniqueRectangles(); got != 0 {
+		t.Errorf("Found %d rectangles in a single square", got)
+	}
+}
+
+// Several solutions, on which a unique rectangle can be found but is not
+// sound
+const urMultipleSolutions = "..657849252.13.7.8.87.2953..6.41..8797486312585179.643....4...669.351.7.74528...9"
+
+func TestUniqueRectangleNeedsUniquePuzzle(t *testing.T) {
+	s := loadString(t, urMultipleSolutions, SingleLine())
+	if result, err := s.SolveDetailed(); result != MultipleSolutions || err != ErrMultipleSolutions {
+		t.Errorf("Got %v, %v; want %v", result, err, MultipleSolutions)
+	}
+	for _, step := range s.Steps() {
+		if step.Technique == "unique rectangle" {
+			t.Errorf("Took %s on a puzzle with several solutions", step.Describe())
+		}
+	}
+
+	s = loadString(t, urMultipleSolutions, SingleLine())
+	s.SetAssumeUnique(true)
+	s.SolveDetailed()
+	used := false
+	for _, step := range s.Steps() {
+		used = used || step.Technique == "unique rectangle"
+	}
+	if !used {
+		t.Error("Unique rectangle not used when the puzzle is assumed unique")
+	}
+}
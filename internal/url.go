@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Boards are packed two cells per byte, with 0 for blank cells, and encoded
+// as unpadded base64url.
+const packedLength = (cellCount + 1) / 2
+
+func (s *Sudoku) EncodeURL() string {
+	packed := make([]byte, packedLength)
+	for i, cell := range s.Cells() {
+		packed[i/2] |= byte(cell.value) << (4 * (1 - i%2))
+	}
+	return base64.RawURLEncoding.EncodeToString(packed)
+}
+
+func DecodeURL(encoded string) (*Sudoku, error) {
+	packed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(packed) != packedLength {
+		return nil, fmt.Errorf("Encoded board has %d bytes, expected %d", len(packed), packedLength)
+	}
+
+	board := [Size][Size]int{}
+	for i := 0; i < cellCount; i++ {
+		value := int(packed[i/2]>>(4*(1-i%2))) & 0xf
+		if value > Size {
+			return nil, fmt.Errorf("Encoded cell %d,%d has invalid value %d", i/Size+1, i%Size+1, value)
+		}
+		board[i/Size][i%Size] = value
+	}
+
+	return NewSudoku(board)
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"io"
+	"testing"
+)
+
+func TestEncodeURLRoundTrip(t *testing.T) {
+	s := loadPuzzle(t, "medium.txt")
+	encoded := s.EncodeURL()
+
+	decoded, err := DecodeURL(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded.SetOutput(io.Discard)
+	if decoded.Grid() != s.Grid() {
+		t.Errorf("Decoded\n%v\nwant\n%v", decoded, s)
+	}
+}
+
+func TestDecodeURLRejectsBadInput(t *testing.T) {
+	valid := loadPuzzle(t, "medium.txt").EncodeURL()
+	for name, encoded := range map[string]string{
+		"not base64":   "!" + valid[1:],
+		"too short":    valid[:len(valid)-2],
+		"value over 9": "_" + valid[1:],
+	} {
+		if _, err := DecodeURL(encoded); err == nil {
+			t.Errorf("Decoded a board that is %s", name)
+		}
+	}
+}
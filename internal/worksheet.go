@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteWorksheet solves a copy of the puzzle and writes a walkthrough: the
+// starting board, then for each round of techniques the steps it took and the
+// board it left, and finally whether the puzzle was solved. There is one
+// round for each level of PropagationDepth.
+func (s *Sudoku) WriteWorksheet(w io.Writer) {
+	clone := s.quietClone()
+
+	fmt.Fprintln(w, s.tr("Puzzle"))
+	clone.WriteBoard(w)
+
+	max := clone.safeTier()
+	for round := 1; ; round++ {
+		seen := len(clone.steps)
+		moves, err := clone.round(max)
+
+		if moves > 0 {
+			fmt.Fprintf(w, s.tr("Round %d")+"\n", round)
+			for _, step := range clone.steps[seen:] {
+				fmt.Fprintf(w, "  %s\n", step.Message)
+			}
+			clone.WriteBoard(w)
+		}
+
+		if err != nil {
+			fmt.Fprintln(w, err)
+			return
+		}
+		if moves == 0 {
+			break
+		}
+	}
+
+	if remaining := len(clone.Cells().UnsetOnly()); remaining > 0 {
+		fmt.Fprintf(w, s.tr("Stuck with %d cells left")+"\n", remaining)
+	} else {
+		fmt.Fprintln(w, s.tr("Solved"))
+	}
+}
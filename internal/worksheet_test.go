@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var worksheetRound = regexp.MustCompile(`(?m)^Round \d+$`)
+
+func TestWriteWorksheet(t *testing.T) {
+	// expert2.txt takes a unique rectangle in its first round
+	for _, name := range []string{"medium.txt", "expert1.txt", "expert2.txt"} {
+		s := loadPuzzle(t, name)
+
+		var out strings.Builder
+		s.WriteWorksheet(&out)
+
+		if got, want := len(worksheetRound.FindAllString(out.String(), -1)), s.PropagationDepth(); got != want {
+			t.Errorf("%s: %d snapshots, want the propagation depth %d", name, got, want)
+		}
+		if !strings.HasPrefix(out.String(), "Puzzle\n") || !strings.HasSuffix(out.String(), "Solved\n") {
+			t.Errorf("%s: got\n%s", name, out.String())
+		}
+	}
+}
+
+func TestWriteWorksheetStuck(t *testing.T) {
+	var out strings.Builder
+	loadPuzzle(t, "expert3.txt").WriteWorksheet(&out)
+	if !strings.HasSuffix(out.String(), "Stuck with 39 cells left\n") {
+		t.Errorf("Got\n%s", out.String())
+	}
+}
@@ -1,32 +1,168 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/sudoku-solver/internal"
+	"io"
 	"os"
 )
 
+// Exit codes
+const (
+	exitSolved     = 0
+	exitParseError = 1 // bad arguments or the puzzle could not be loaded
+	exitUnsolved   = 2 // the puzzle has no unique logical solution, or none could be generated
+)
+
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Printf("Usage: %s <file>\n", os.Args[0])
-		os.Exit(1)
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run carries out the command given by args and returns its exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "generate" {
+		return generate(args[1:], stdout, stderr)
+	}
+
+	// solve is the default, so it may be left out
+	if len(args) > 0 && args[0] == "solve" {
+		args = args[1:]
+	}
+	return solve(args, stdin, stdout, stderr)
+}
+
+func solve(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("solve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	candidates := flags.Bool("candidates", false, "print the remaining candidates after solving")
+	format := flags.String("format", "pretty", "how to print the solution: pretty, line or json")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s [solve] [-candidates] [-format pretty|line|json] [<file> | -]\n", os.Args[0])
+		fmt.Fprintf(stderr, "       %s generate [-clues N] [-difficulty D]\n", os.Args[0])
+		fmt.Fprintln(stderr, "Reads the puzzle from standard input when the file is - or omitted.")
+		fmt.Fprintln(stderr, "Exits with 0 when solved, 1 when the puzzle cannot be loaded and 2 when it cannot be solved.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return parseFailure(err)
 	}
 
-	path := os.Args[1]
+	if *format != "pretty" && *format != "line" && *format != "json" {
+		fmt.Fprintf(stderr, "Unknown format %q\n", *format)
+		flags.Usage()
+		return exitParseError
+	}
+
+	path := flags.Arg(0)
+	switch {
+	case flags.NArg() == 1 && path != "-":
+	case flags.NArg() == 1 || (flags.NArg() == 0 && isPipe(stdin)):
+		path = ""
+	default:
+		flags.Usage()
+		return exitParseError
+	}
+
+	// In the line and json formats only the solution goes to standard output,
+	// and the solver's progress to standard error
+	log := stdout
+	if *format != "pretty" {
+		log = stderr
+	}
 
-	s, err := internal.NewSudokuFromFile(path)
+	var s *internal.Sudoku
+	var err error
+	if path == "" {
+		s, err = internal.NewSudokuFromReader(stdin, internal.Output(log))
+	} else {
+		s, err = internal.NewSudokuFromFile(path, internal.Output(log))
+	}
+
+	if s != nil {
+		fmt.Fprintf(log, "Parsed as %s\n", s.Format())
+	}
 
 	if err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(log, err)
 		if s != nil {
-			s.PrintBoard()
+			s.WriteBoard(log)
+		}
+		return exitParseError
+	}
+
+	if result, err := s.SolveDetailed(); result != internal.Solved {
+		fmt.Fprintln(log, err)
+		s.WriteBoard(log)
+		s.WriteMoves(log)
+		return exitUnsolved
+	}
+
+	// The pretty board was already printed by the solver
+	switch *format {
+	case "line":
+		fmt.Fprintln(stdout, s)
+	case "json":
+		json.NewEncoder(stdout).Encode(s)
+	}
+
+	if *candidates {
+		s.WriteMoves(stdout)
+	}
+	return exitSolved
+}
+
+func generate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	clues := flags.Int("clues", 0, "stop removing clues once this many remain; 0 removes as many as possible")
+	difficulty := flags.String("difficulty", "singles", "hardest techniques needed: singles, intersections, subsets, chains or uniqueness")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s generate [-clues N] [-difficulty D]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return parseFailure(err)
+	}
+
+	tier, err := internal.ParseTier(*difficulty)
+	if err != nil || flags.NArg() != 0 || *clues < 0 {
+		if err != nil {
+			fmt.Fprintln(stderr, err)
 		}
-		return
+		flags.Usage()
+		return exitParseError
+	}
+
+	s, err := internal.Generate(internal.GenerateOptions{MaxTier: tier, Clues: *clues})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUnsolved
+	}
+	s.WriteBoard(stdout)
+	return exitSolved
+}
+
+// parseFailure returns the exit code for an error from parsing flags. Asking
+// for help with -h is not a failure.
+func parseFailure(err error) int {
+	if err == flag.ErrHelp {
+		return exitSolved
 	}
+	return exitParseError
+}
 
-	if err = s.Solve(); err != nil {
-		fmt.Println(err)
-		s.PrintBoard()
-		s.PrintMoves()
+// isPipe reports whether stdin has input waiting, rather than being a
+// terminal. Readers other than files always count as input.
+func isPipe(stdin io.Reader) bool {
+	f, ok := stdin.(*os.File)
+	if !ok {
+		return true
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice == 0
 }
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sudoku-solver/internal"
+)
+
+// runCLI runs the command with args, reading stdin, and returns its exit code
+// and what it wrote to standard output and standard error.
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func puzzle(name string) string {
+	return filepath.Join("puzzles", name)
+}
+
+func TestCandidatesFlag(t *testing.T) {
+	_, without, _ := runCLI(t, "", puzzle("medium.txt"))
+	code, with, _ := runCLI(t, "", "-candidates", puzzle("medium.txt"))
+	if code != exitSolved {
+		t.Fatalf("Exit code %d, want %d", code, exitSolved)
+	}
+	if !strings.HasPrefix(with, without) || len(with) == len(without) {
+		t.Errorf("-candidates did not add the candidates after the solution:\n%s", with)
+	}
+}
+
+func TestSolveFromStdin(t *testing.T) {
+	text, err := os.ReadFile(puzzle("medium.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, want, _ := runCLI(t, "", "-format", "line", puzzle("medium.txt"))
+
+	for _, args := range [][]string{{"-format", "line"}, {"-format", "line", "-"}} {
+		code, got, stderr := runCLI(t, string(text), args...)
+		if code != exitSolved || got != want {
+			t.Errorf("%v: exit code %d, output %q, want %d, %q\n%s", args, code, got, exitSolved, want, stderr)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		stdin string
+		args  []string
+		want  int
+	}{
+		{"solved", "", []string{puzzle("medium.txt")}, exitSolved},
+		{"needs guessing", "", []string{puzzle("expert3.txt")}, exitUnsolved},
+		{"several solutions", "", []string{puzzle("blank.txt")}, exitUnsolved},
+		{"missing file", "", []string{puzzle("missing.txt")}, exitParseError},
+		{"conflicting givens", "11" + strings.Repeat(".", 79), []string{"-"}, exitParseError},
+		{"unknown flag", "", []string{"-bogus"}, exitParseError},
+		{"unknown format", "", []string{"-format", "xml", puzzle("medium.txt")}, exitParseError},
+		{"too many files", "", []string{puzzle("medium.txt"), puzzle("hard1.txt")}, exitParseError},
+	} {
+		if code, stdout, stderr := runCLI(t, test.stdin, test.args...); code != test.want {
+			t.Errorf("%s: exit code %d, want %d\n%s%s", test.name, code, test.want, stdout, stderr)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	const solution = "316578492529134768487629531263415987974863125851792643138947256692351874745286319"
+
+	code, stdout, stderr := runCLI(t, "", "-format", "line", puzzle("medium.txt"))
+	if code != exitSolved || stdout != solution+"\n" {
+		t.Errorf("line: exit code %d, output %q", code, stdout)
+	}
+	if !strings.Contains(stderr, "Initialized Board") {
+		t.Errorf("line: progress not written to standard error:\n%s", stderr)
+	}
+
+	code, stdout, _ = runCLI(t, "", "-format", "json", puzzle("medium.txt"))
+	var grid [9][9]int
+	if err := json.Unmarshal([]byte(stdout), &grid); code != exitSolved || err != nil {
+		t.Fatalf("json: exit code %d, output %q: %v", code, stdout, err)
+	}
+	for i, c := range solution {
+		if grid[i/9][i%9] != int(c-'0') {
+			t.Fatalf("json: got %v", grid)
+		}
+	}
+
+	code, stdout, stderr = runCLI(t, "", "-format", "pretty", puzzle("medium.txt"))
+	if code != exitSolved || stderr != "" || !strings.Contains(stdout, "Initialized Board") || !strings.HasSuffix(stdout, "7 4 5|2 8 6|3 1 9\n\n") {
+		t.Errorf("pretty: exit code %d, output\n%s%s", code, stdout, stderr)
+	}
+}
+
+func TestSolveSubcommand(t *testing.T) {
+	_, want, _ := runCLI(t, "", puzzle("medium.txt"))
+	code, got, _ := runCLI(t, "", "solve", puzzle("medium.txt"))
+	if code != exitSolved || got != want {
+		t.Errorf("solve exited with %d and printed\n%s\nwant\n%s", code, got, want)
+	}
+}
+
+func TestGenerateSubcommand(t *testing.T) {
+	code, out, _ := runCLI(t, "", "generate", "-clues", "40", "-difficulty", "singles")
+	if code != exitSolved {
+		t.Fatalf("Exit code %d, want %d:\n%s", code, exitSolved, out)
+	}
+
+	s, err := internal.NewSudokuFromString(boardLine(out), internal.SingleLine(), internal.Output(io.Discard))
+	if err != nil {
+		t.Fatalf("Printed an unreadable board: %v\n%s", err, out)
+	}
+	if clues := internal.Size*internal.Size - len(s.Cells().UnsetOnly()); clues < 40 {
+		t.Errorf("Generated %d clues, want at least 40", clues)
+	}
+	if err := s.SolveLogicalOnly(internal.TierSingles); err != nil {
+		t.Errorf("Generated puzzle needs more than singles: %v", err)
+	}
+}
+
+func TestUnknownFormatKeepsStdoutClean(t *testing.T) {
+	for _, args := range [][]string{
+		{"-format", "xml", puzzle("medium.txt")},
+		{"solve", "-format", "xml", puzzle("medium.txt")},
+	} {
+		code, stdout, stderr := runCLI(t, "", args...)
+		if code != exitParseError || stdout != "" || !strings.Contains(stderr, `Unknown format "xml"`) {
+			t.Errorf("%s: exit code %d, output %q\n%s", strings.Join(args, " "), code, stdout, stderr)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	for _, args := range [][]string{{"-h"}, {"solve", "-h"}, {"generate", "-h"}} {
+		code, stdout, stderr := runCLI(t, "", args...)
+		if code != exitSolved || stdout != "" || !strings.Contains(stderr, "Usage:") {
+			t.Errorf("%s: exit code %d, output %q\n%s", strings.Join(args, " "), code, stdout, stderr)
+		}
+	}
+}
+
+func TestGenerateBadArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{"-bogus"},
+		{"-clues", "many"},
+		{"-clues", "-1"},
+		{"-difficulty", "impossible"},
+		{"extra"},
+	} {
+		code, stdout, stderr := runCLI(t, "", append([]string{"generate"}, args...)...)
+		if code != exitParseError || stdout != "" || !strings.Contains(stderr, "Usage:") {
+			t.Errorf("generate %s exited with %d and printed\n%s%s", strings.Join(args, " "), code, stdout, stderr)
+		}
+	}
+}
+
+// boardLine reads a board as drawn by WriteBoard back into a single line,
+// taking every other character of each row.
+func boardLine(board string) string {
+	var line strings.Builder
+	for _, row := range strings.Split(board, "\n") {
+		if len(row) == 0 || strings.HasPrefix(row, "-") {
+			continue
+		}
+		for i := 0; i < len(row); i += 2 {
+			if row[i] == ' ' {
+				line.WriteByte('.')
+			} else {
+				line.WriteByte(row[i])
+			}
+		}
+	}
+	return line.String()
+}